@@ -1,18 +1,39 @@
 package blockstore
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 const PathPostfix = "berith-swap/blockstore"
 
+// BlockRecord는 처리를 마친 블록의 해시와 이전 record 이후 감지된 deposit 트랜잭션을 기록합니다.
+type BlockRecord struct {
+	Number   uint64      `json:"number"`
+	Hash     common.Hash `json:"hash"`
+	Deposits []string    `json:"deposits,omitempty"`
+}
+
+// NonceRecord는 마지막으로 감지한 Deposit 이벤트의 depositNonce를 기록합니다.
+type NonceRecord struct {
+	Nonce       uint64 `json:"nonce"`
+	BlockNumber uint64 `json:"blockNumber"`
+	TxHash      string `json:"txHash"`
+}
+
 type Blockstore struct {
 	path      string
 	fullPath  string
+	hashPath  string
+	noncePath string
 	chainName string
+	lock      sync.Mutex
 }
 
 func NewBlockstore(path, chainName string) (*Blockstore, error) {
@@ -28,11 +49,19 @@ func NewBlockstore(path, chainName string) (*Blockstore, error) {
 	return &Blockstore{
 		path:      path,
 		fullPath:  filepath.Join(path, fileName),
+		hashPath:  filepath.Join(path, getHashFileName(chainName)),
+		noncePath: filepath.Join(path, getNonceFileName(chainName)),
 		chainName: chainName,
 	}, nil
 }
 
 func (b *Blockstore) StoreBlock(block *big.Int) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.storeBlock(block)
+}
+
+func (b *Blockstore) storeBlock(block *big.Int) error {
 	if _, err := os.Stat(b.path); os.IsNotExist(err) {
 		errr := os.MkdirAll(b.path, os.ModePerm)
 		if errr != nil {
@@ -49,6 +78,11 @@ func (b *Blockstore) StoreBlock(block *big.Int) error {
 	return nil
 }
 
+// HasLatestBlock은 blockstore에 저장된 블록 번호가 있는지 확인합니다.
+func (b *Blockstore) HasLatestBlock() (bool, error) {
+	return fileExists(b.fullPath)
+}
+
 func (b *Blockstore) TryLoadLatestBlock() (*big.Int, error) {
 	exists, err := fileExists(b.fullPath)
 	if err != nil {
@@ -66,10 +100,75 @@ func (b *Blockstore) TryLoadLatestBlock() (*big.Int, error) {
 	return big.NewInt(0), nil
 }
 
+// StoreBlockRecords는 reorg 감지를 위해 최근 처리한 블록들의 record를 저장합니다.
+func (b *Blockstore) StoreBlockRecords(records []BlockRecord) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.writeJSON(b.hashPath, records)
+}
+
+// TryLoadBlockRecords는 저장된 블록 record를 불러옵니다. 파일이 없다면 빈 slice를 반환합니다.
+func (b *Blockstore) TryLoadBlockRecords() ([]BlockRecord, error) {
+	records := []BlockRecord{}
+	if err := readJSON(b.hashPath, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// StoreNonce는 마지막으로 감지한 depositNonce를 저장합니다.
+func (b *Blockstore) StoreNonce(record NonceRecord) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.writeJSON(b.noncePath, record)
+}
+
+// TryLoadNonce는 저장된 depositNonce를 불러옵니다. 파일이 없다면 빈 record를 반환합니다.
+func (b *Blockstore) TryLoadNonce() (NonceRecord, error) {
+	var record NonceRecord
+	err := readJSON(b.noncePath, &record)
+	return record, err
+}
+
+func (b *Blockstore) writeJSON(path string, v interface{}) error {
+	if _, err := os.Stat(b.path); os.IsNotExist(err) {
+		errr := os.MkdirAll(b.path, os.ModePerm)
+		if errr != nil {
+			return errr
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func readJSON(path string, v interface{}) error {
+	exists, err := fileExists(path)
+	if err != nil || !exists {
+		return err
+	}
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dat, v)
+}
+
 func getFileName(chainName string) string {
 	return fmt.Sprintf("%s.block", chainName)
 }
 
+func getHashFileName(chainName string) string {
+	return fmt.Sprintf("%s.hashes", chainName)
+}
+
+func getNonceFileName(chainName string) string {
+	return fmt.Sprintf("%s.nonce", chainName)
+}
+
 // getHomePath returns the home directory joined with PathPostfix
 func getDefaultPath() (string, error) {
 	home, err := os.UserHomeDir()
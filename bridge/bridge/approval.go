@@ -0,0 +1,52 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// setApprovalThreshold는 운영자 승인이 필요한 예치액의 기준을 설정합니다.
+func (r *ReceiverChain) setApprovalThreshold(chainCfg *config.RawChainConfig) {
+	threshold, err := parseLimit(chainCfg.ApprovalThreshold)
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msgf("invalid approvalThreshold:%s", chainCfg.ApprovalThreshold)
+	}
+	r.approvalThreshold = threshold
+}
+
+// requireReview는 운영자가 승인하지 않은 deposit이 지급 한도를 넘는다면 held 상태로,
+// 승인 기준 이상이라면 pending_approval 상태로 보류하고 true를 반환합니다.
+// 주소 screening은 승인 여부와 관계없이 screenDeposit에서 먼저 확인합니다.
+func (r *ReceiverChain) requireReview(u *limitUsage, m message.DepositMessage) (bool, error) {
+	if r.approvalThreshold != nil && m.Amount.Cmp(r.approvalThreshold) >= 0 {
+		reason := fmt.Sprintf("deposit amount requires approval. amount:%s, threshold:%s", m.Amount.String(), r.approvalThreshold.String())
+		return true, r.requestApproval(m, reason)
+	}
+
+	reason, err := u.check(m)
+	if err != nil {
+		return false, err
+	}
+	if reason != "" {
+		return true, r.holdDeposit(m, reason)
+	}
+	return false, nil
+}
+
+// requestApproval은 deposit을 pending_approval 상태로 변경하여 운영자가 승인할 때까지 지급하지 않습니다.
+func (r *ReceiverChain) requestApproval(m message.DepositMessage, reason string) error {
+	err := r.store.RequestDepositApproval(context.Background(), mariadb.RequestDepositApprovalParams{
+		Reason:       sql.NullString{String: reason, Valid: true},
+		SenderTxHash: m.SenderTxHash,
+	})
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msgf("cannot request deposit approval. hash:%s", m.SenderTxHash)
+		return err
+	}
+	r.c.Logger.Warn().Msgf("%s. wait for operator approval. hash:%s", reason, m.SenderTxHash)
+	return nil
+}
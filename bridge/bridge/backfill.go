@@ -0,0 +1,85 @@
+package bridge
+
+import (
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/util"
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// Backfill은 from부터 to까지의 블록에서 Deposit 이벤트를 다시 탐색하여 지급되지 않은 swap을 찾습니다.
+// dryRun이 true라면 누락된 swap을 출력만 하고, 아니라면 deposit queue에 저장하여 실행 중인 Receiver가 지급하도록 합니다.
+// Sender와 같은 방식으로 확정된 블록까지만 탐색하며, quorum 검증이 설정되어 있다면 검증에 실패한 deposit은 held 상태로 저장합니다.
+func (b *Bridge) Backfill(from, to *big.Int, dryRun bool) error {
+	_, confirmedBlock, err := b.sc.confirmedBlock()
+	if err != nil {
+		return fmt.Errorf("cannot get confirmed block. err:%w", err)
+	}
+	if to.Cmp(confirmedBlock) == 1 {
+		return fmt.Errorf("block range is not confirmed yet. to:%s, confirmed:%s", to.String(), confirmedBlock.String())
+	}
+
+	step := b.sc.blockRange
+	b.sc.c.Logger.Info().Msgf("backfill deposit events. from:%s, to:%s, dryRun:%v", from.String(), to.String(), dryRun)
+
+	var found, missing int
+	for start := new(big.Int).Set(from); start.Cmp(to) <= 0; start.Add(start, step) {
+		end := new(big.Int).Add(start, step)
+		end.Sub(end, big.NewInt(1))
+		if end.Cmp(to) == 1 {
+			end.Set(to)
+		}
+
+		logs, err := b.sc.fetchDepositLogs(start, end)
+		if err != nil {
+			return err
+		}
+		msgs, err := b.sc.getDepositMessages(logs)
+		if err != nil {
+			return err
+		}
+
+		unpaid := make([]message.DepositMessage, 0, len(msgs))
+		for _, m := range msgs {
+			found++
+			if valErr := util.ValidateStruct(m); valErr != nil {
+				b.sc.c.Logger.Warn().Msgf("Invalid deposit message. %s", valErr.Error())
+				continue
+			}
+
+			swapped, err := b.rc.isSwapped(m.SenderTxHash)
+			if err != nil {
+				return err
+			}
+			if swapped {
+				continue
+			}
+
+			missing++
+			b.sc.c.Logger.Info().Msgf("missing payout. block:%d, sender tx:%s, receiver:%s, value:%s", m.BlockNumber, m.SenderTxHash, m.Receiver.Hex(), m.Amount.String())
+			unpaid = append(unpaid, m)
+		}
+		if dryRun || len(unpaid) == 0 {
+			continue
+		}
+
+		verified, held, err := b.sc.verifyDeposits(unpaid)
+		if err != nil {
+			return fmt.Errorf("cannot verify deposits. from:%s, to:%s, err:%w", start.String(), end.String(), err)
+		}
+		// 이미 저장된 deposit은 무시되므로 처리 중인 deposit의 상태는 변경되지 않는다.
+		if err := b.sc.EnqueueMsgs(verified); err != nil {
+			return err
+		}
+		if err := enqueueHeldDeposits(context.Background(), b.sc.store, held); err != nil {
+			return err
+		}
+		for _, h := range held {
+			b.sc.c.Logger.Warn().Msgf("%s. hold deposit for manual review. hash:%s", h.reason, h.msg.SenderTxHash)
+		}
+	}
+
+	b.sc.c.Logger.Info().Msgf("backfill finished. deposits:%d, missing payouts:%d, dryRun:%v", found, missing, dryRun)
+	return nil
+}
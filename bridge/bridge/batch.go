@@ -0,0 +1,238 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/transaction"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	DefaultBatchSize   = 50
+	DefaultBatchWindow = time.Second * 10
+)
+
+// setBatchTransferContract는 batchAddress가 설정되어 있다면 batch 전송 컨트랙트를 설정합니다.
+func (r *ReceiverChain) setBatchTransferContract(chainCfg *config.RawChainConfig) {
+	if chainCfg.BatchAddress == "" {
+		return
+	}
+
+	err := r.c.EvmClient.EnsureHasBytecode(common.HexToAddress(chainCfg.BatchAddress))
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msgf("batch transfer contract dosen't exist this chain url:%s", r.c.Endpoint)
+	}
+
+	c, err := contract.InitBatchTransferContract(r.c.EvmClient, chainCfg.BatchAddress, &r.c.Logger)
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msg("cannot init batch transfer contract")
+	}
+
+	batchSize, err := strconv.Atoi(chainCfg.BatchSize)
+	if err != nil || batchSize <= 0 {
+		r.c.Logger.Info().Msgf("batch size is not set. set default:%d", DefaultBatchSize)
+		batchSize = DefaultBatchSize
+	}
+
+	batchWindow, err := time.ParseDuration(chainCfg.BatchWindow)
+	if err != nil || batchWindow <= 0 {
+		r.c.Logger.Info().Msgf("batch window is not set. set default:%s", DefaultBatchWindow)
+		batchWindow = DefaultBatchWindow
+	}
+
+	r.batchContract = c
+	r.batchSize = batchSize
+	r.batchWindow = batchWindow
+	r.c.Logger.Info().Msgf("deposits will be paid in batches. size:%d, window:%s", batchSize, batchWindow)
+}
+
+// addToBatch는 deposit을 다음 batch 전송에 추가합니다.
+func (r *ReceiverChain) addToBatch(m message.DepositMessage) {
+	if len(r.batch) == 0 {
+		r.batchStarted = time.Now()
+	}
+	r.batch = append(r.batch, m)
+	r.markInFlight(m)
+}
+
+// flushBatch는 batch가 가득 찼거나 batchWindow가 지났다면 모은 deposit을 한 번의 트랜잭션으로 전송합니다.
+func (r *ReceiverChain) flushBatch() error {
+	if len(r.batch) == 0 {
+		return nil
+	}
+	if len(r.batch) < r.batchSize && time.Since(r.batchStarted) < r.batchWindow {
+		return nil
+	}
+
+	for len(r.batch) > 0 {
+		n := r.batchSize
+		if n > len(r.batch) {
+			n = len(r.batch)
+		}
+		batch := r.batch[:n:n]
+		r.batch = r.batch[n:]
+
+		var err error
+		if submitErr := r.submitBatch(batch); submitErr != nil {
+			r.unmarkInFlight(batch...)
+			// batch의 모든 deposit을 같은 전송 에러로 재시도 대상으로 기록한다.
+			for _, m := range batch {
+				if err = r.handleSubmitError(m, submitErr); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			r.unmarkInFlight(r.batch...)
+			r.batch = nil
+			return err
+		}
+	}
+	r.batch = nil
+	return nil
+}
+
+// submitBatch는 batch 전송 트랜잭션을 전송하고 receipt는 별도의 goroutine에서 확인합니다.
+func (r *ReceiverChain) submitBatch(batch []message.DepositMessage) error {
+	select {
+	case r.slots <- struct{}{}:
+	case <-r.stop:
+		return errReceiverStopped
+	}
+
+	recipients := make([]common.Address, len(batch))
+	quotes := make([]quote, len(batch))
+	amounts := make([]*big.Int, len(batch))
+	total := new(big.Int)
+	for i, m := range batch {
+		recipients[i] = m.Receiver
+		quotes[i] = r.quote(m)
+		amounts[i] = quotes[i].amount
+		total.Add(total, amounts[i])
+	}
+
+	// 이전 batch 트랜잭션이 아직 allowance를 사용하지 않았을 수 있으므로 처리 중인 모든 deposit의 지급액을 승인한다.
+	err := r.ensureBatchAllowance(r.reservedAmount())
+	if err != nil {
+		<-r.slots
+		return err
+	}
+
+	opts := transaction.TransactOptions{
+		GasLimit: r.c.GasLimit.Uint64(),
+		OnSigned: func(hash common.Hash, nonce uint64) error {
+			return r.markSubmitted(hash, nonce, batch, quotes)
+		},
+	}
+	txHash, err := r.batchContract.SubmitBatchTransfer(*r.erc20Contract.ContractAddress(), recipients, amounts, opts)
+	if err != nil {
+		<-r.slots
+		r.c.Logger.Error().Err(err).Msgf("batch transaction submit failed. deposits:%d", len(batch))
+		return err
+	}
+	r.c.Logger.Info().Msgf("submitted batch token transfer. deposits:%d, total:%s, Tx Hash: %s", len(batch), total.String(), txHash.Hex())
+
+	go r.trackBatch(batch, txHash)
+	return nil
+}
+
+// trackBatch는 batch 전송 트랜잭션의 receipt를 기다리고 각 deposit의 결과를 저장합니다.
+// batch 트랜잭션이 revert 되었다면 deposit을 detected 상태로 되돌리고 개별 트랜잭션으로 지급하도록 queueFallback에 전달합니다.
+func (r *ReceiverChain) trackBatch(batch []message.DepositMessage, txHash *common.Hash) {
+	rec, err := r.batchContract.WaitAndReturnTxReceipt(txHash)
+	switch {
+	case err == nil:
+		r.c.Logger.Info().Msgf("receive batch tx receipt successfully. Block: %s, Tx Hash: %s, deposits:%d", rec.BlockNumber, txHash.Hex(), len(batch))
+		for _, m := range batch {
+			if err = r.recordPayout(m, txHash); err != nil {
+				break
+			}
+		}
+	case rec != nil && rec.Status == types.ReceiptStatusFailed:
+		r.c.Logger.Warn().Err(err).Msgf("batch transfer reverted. fall back to individual transfers. Tx Hash: %s", txHash.Hex())
+		err = r.resetDeposits(batch...)
+		if err == nil {
+			// 개별 지급이 끝날 때까지 처리 중으로 남겨두어 다시 batch에 추가되지 않도록 한다.
+			r.queueFallback(batch)
+			<-r.slots
+			return
+		}
+	default:
+		r.c.Logger.Error().Err(err).Msgf("cannot get batch tx receipt. reconcile later. hash:%s", txHash.Hex())
+		err = nil
+	}
+
+	r.unmarkInFlight(batch...)
+	<-r.slots
+
+	if err != nil {
+		r.confirmErr <- err
+	}
+}
+
+// queueFallback은 revert 된 batch의 deposit을 개별 트랜잭션으로 지급하도록 queue 조회 goroutine에 전달합니다.
+// 토큰 전송 트랜잭션의 nonce와 잔액 확인이 겹치지 않도록 개별 지급은 processQueue에서만 전송합니다.
+func (r *ReceiverChain) queueFallback(batch []message.DepositMessage) {
+	r.fallbackLock.Lock()
+	r.fallback = append(r.fallback, batch...)
+	r.fallbackLock.Unlock()
+
+	select {
+	case r.resume <- struct{}{}:
+	default:
+	}
+}
+
+// payFallback은 revert 된 batch의 deposit을 개별 트랜잭션으로 지급하고, 실패하면 재시도 대상으로 기록합니다.
+func (r *ReceiverChain) payFallback() error {
+	r.fallbackLock.Lock()
+	msgs := r.fallback
+	r.fallback = nil
+	r.fallbackLock.Unlock()
+
+	for i, m := range msgs {
+		r.unmarkInFlight(m)
+
+		liq, err := r.loadLiquidity()
+		if err == nil {
+			err = liq.take(r.quote(m).amount, 1)
+		}
+		if err == nil {
+			err = r.submitInFlight(m)
+		} else {
+			err = r.handleSubmitError(m, err)
+		}
+		if err != nil {
+			r.unmarkInFlight(msgs[i+1:]...)
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureBatchAllowance는 batch 전송 컨트랙트가 owner의 토큰을 전송할 수 있도록 allowance를 확인하고 부족하면 amount 만큼 승인합니다.
+func (r *ReceiverChain) ensureBatchAllowance(amount *big.Int) error {
+	spender := *r.batchContract.ContractAddress()
+	allowance, err := r.erc20Contract.GetAllowance(r.c.EvmClient.From(), spender)
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msg("cannot get allowance of batch transfer contract")
+		return err
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil
+	}
+
+	r.c.Logger.Info().Msgf("approve tokens to batch transfer contract. allowance:%s, required:%s", allowance.String(), amount.String())
+	_, err = r.erc20Contract.Approve(spender, amount, transaction.TransactOptions{GasLimit: r.c.GasLimit.Uint64()})
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msg("cannot approve tokens to batch transfer contract")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// batch 전송에 실패하면 batch의 모든 deposit이 같은 전송 에러로 재시도 대상이 되는가?
+func TestFlushBatchSubmitError(t *testing.T) {
+	logger := zerolog.Nop()
+	client := newTestRPCClient(t, func(method string, params []json.RawMessage) interface{} {
+		// allowance 조회에 응답하지 않아 batch 트랜잭션을 전송하지 못한다.
+		return nil
+	})
+
+	var (
+		msgs []message.DepositMessage
+		rows []mariadb.BersDepositQueue
+	)
+	for i := int64(1); i <= 2; i++ {
+		m := message.NewDepositMessage(1, common.Hash{}, uint64(i), common.BigToAddress(big.NewInt(i)), common.BigToAddress(big.NewInt(i)), big.NewInt(i), common.BigToHash(big.NewInt(i)).Hex())
+		msgs = append(msgs, m)
+		rows = append(rows, mariadb.BersDepositQueue{SenderTxHash: m.SenderTxHash, Status: store.DepositDetected, RetryCount: 1})
+	}
+	st, db := newTestStore(t, rows...)
+
+	conversion, err := newConversion(&config.RawChainConfig{})
+	require.NoError(t, err)
+	fee, err := newFeeModel(&config.RawChainConfig{})
+	require.NoError(t, err)
+	r := &ReceiverChain{
+		c:             &chain.Chain{EvmClient: client, Logger: logger, GasLimit: big.NewInt(100000)},
+		store:         st,
+		erc20Contract: contract.NewERC20Contract(client, common.HexToAddress("0x1"), nil, &logger),
+		batchContract: contract.NewBatchTransferContract(client, common.HexToAddress("0x2"), nil, &logger),
+		batchSize:     DefaultBatchSize,
+		batchWindow:   DefaultBatchWindow,
+		slots:         make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		inFlight:      make(map[string]*big.Int),
+		retry:         retryPolicy{maxRetries: DefaultMaxRetries, backoff: time.Second, maxBackoff: time.Second},
+		conversion:    conversion,
+		fee:           fee,
+	}
+	for _, m := range msgs {
+		r.addToBatch(m)
+	}
+	r.batchStarted = time.Now().Add(-r.batchWindow)
+
+	require.NoError(t, r.flushBatch())
+	require.Empty(t, r.batch)
+	require.Zero(t, r.inFlightCount())
+
+	retries := db.executed("ScheduleDepositRetry")
+	require.Len(t, retries, len(msgs))
+	for i, args := range retries {
+		require.Equal(t, int64(2), args[0])
+		lastError, ok := args[1].(string)
+		require.True(t, ok)
+		require.NotEmpty(t, lastError)
+		require.Equal(t, msgs[i].SenderTxHash, args[3])
+	}
+}
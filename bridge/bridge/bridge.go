@@ -3,7 +3,9 @@ package bridge
 import (
 	"berith-swap/bridge/blockstore"
 	"berith-swap/bridge/config"
-	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"context"
+	"fmt"
 
 	"github.com/rs/zerolog/log"
 )
@@ -16,21 +18,21 @@ const (
 
 // Bridge는 SenderChain과 ReceiverChain을 생성하고 시작합니다.
 type Bridge struct {
-	sc *SenderChain
-	rc *ReceiverChain
+	sc   *SenderChain
+	rc   *ReceiverChain
+	lock *store.Lock // 같은 계정으로 트랜잭션을 서명하는 다른 프로세스를 막는 lock
 }
 
 // NewBridge는 SenderChain과 ReceiverChain을 생성합니다.
 func NewBridge(cfg *config.Config) *Bridge {
 	br := new(Bridge)
 
-	msgChan := make(chan message.DepositMessage)
 	bs, err := blockstore.NewBlockstore(cfg.BlockStorePath, cfg.ChainConfig[0].Name)
 	if err != nil {
 		log.Error().Err(err).Msg("cannot initialize block store")
 	}
-	sc := NewSenderChain(msgChan, cfg, SenderIdx, bs)
-	rc := NewReceiverChain(msgChan, cfg, ReceiverIdx, bs)
+	sc := NewSenderChain(cfg, SenderIdx, bs)
+	rc := NewReceiverChain(cfg, ReceiverIdx)
 
 	br.sc = sc
 	br.rc = rc
@@ -38,7 +40,13 @@ func NewBridge(cfg *config.Config) *Bridge {
 }
 
 // Start는 SenderChain과 ReceiverChain을 시작합니다.
+// 같은 계정으로 트랜잭션을 서명하는 다른 Bridge나 명령이 실행 중이라면 nonce가 충돌하므로 시작하지 않습니다.
 func (b *Bridge) Start() error {
+	err := b.lockSigners()
+	if err != nil {
+		return fmt.Errorf("cannot start bridge. another bridge or command may be signing with the same accounts. err:%w", err)
+	}
+
 	ch := make(chan error)
 	go b.sc.start(ch)
 	go b.rc.start(ch)
@@ -48,6 +56,28 @@ func (b *Bridge) Start() error {
 
 // Stop는 SenderChain과 ReceiverChain을 종료합니다.
 func (b *Bridge) Stop() {
+	b.unlockSigners()
 	b.sc.Stop()
 	b.rc.Stop()
 }
+
+// lockSigners는 Receiver 계정으로 구분되는 named lock을 가져와 같은 계정으로 트랜잭션을 서명하는 프로세스가 하나만 실행되도록 합니다.
+// 각 프로세스는 nonce를 따로 관리하므로 동시에 트랜잭션을 서명하면 같은 nonce를 사용할 수 있습니다.
+func (b *Bridge) lockSigners() error {
+	lock, err := b.rc.store.AcquireLock(context.Background(), fmt.Sprintf("berith-swap:%s", b.rc.c.EvmClient.From().Hex()))
+	if err != nil {
+		return err
+	}
+	b.lock = lock
+	return nil
+}
+
+func (b *Bridge) unlockSigners() {
+	if b.lock == nil {
+		return
+	}
+	if err := b.lock.Release(); err != nil {
+		log.Error().Err(err).Msg("cannot release signer lock")
+	}
+	b.lock = nil
+}
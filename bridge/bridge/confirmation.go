@@ -0,0 +1,67 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"fmt"
+	"math/big"
+)
+
+// 블록 확정 방식
+const (
+	ConfirmByDepth     = "depth"     // 최신 블록으로부터 blockConfirmations 만큼 떨어진 블록을 확정된 것으로 간주
+	ConfirmBySafe      = "safe"      // safe tag 블록까지 확정된 것으로 간주
+	ConfirmByFinalized = "finalized" // finalized tag 블록까지 확정된 것으로 간주
+	ConfirmInstant     = "instant"   // 즉시 완결성을 제공하는 체인에서 최신 블록을 확정된 것으로 간주
+)
+
+// parseConfirmationStrategy는 config에 설정된 블록 확정 방식을 확인합니다.
+// 설정되지 않았다면 blockConfirmations를 사용하는 depth 방식을 사용합니다.
+func parseConfirmationStrategy(strategy string) (string, error) {
+	switch strategy {
+	case "":
+		return ConfirmByDepth, nil
+	case ConfirmByDepth, ConfirmBySafe, ConfirmByFinalized, ConfirmInstant:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown confirmation strategy: %s", strategy)
+	}
+}
+
+// confirmedBlock은 최신 블록 번호와 확정된 블록 번호를 반환합니다.
+func (s *SenderChain) confirmedBlock() (*big.Int, *big.Int, error) {
+	latestBlock, err := s.c.EvmClient.LatestBlockNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	confirmedBlock, err := s.confirmedBlockAt(latestBlock)
+	if err != nil {
+		return nil, nil, err
+	}
+	return latestBlock, confirmedBlock, nil
+}
+
+// confirmedBlockAt은 최신 블록이 head일 때 확정된 블록 번호를 반환합니다.
+func (s *SenderChain) confirmedBlockAt(head *big.Int) (*big.Int, error) {
+	return confirmedBlockAt(s.c, s.confirmation, s.blockConfirmations, head)
+}
+
+// confirmedBlockAt은 체인의 최신 블록이 head일 때 strategy에 따라 확정된 블록 번호를 반환합니다.
+// depth 방식이라면 head로부터 depth 만큼 떨어진 블록을 확정된 것으로 간주합니다.
+func confirmedBlockAt(c *chain.Chain, strategy string, depth, head *big.Int) (*big.Int, error) {
+	switch strategy {
+	case ConfirmInstant:
+		return new(big.Int).Set(head), nil
+	case ConfirmBySafe, ConfirmByFinalized:
+		confirmedBlock, err := c.EvmClient.TaggedBlockNumber(strategy)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get %s block: %w", strategy, err)
+		}
+		// tag 블록은 head보다 앞설 수 없다.
+		if confirmedBlock.Cmp(head) == 1 {
+			return new(big.Int).Set(head), nil
+		}
+		return confirmedBlock, nil
+	default:
+		return new(big.Int).Sub(head, depth), nil
+	}
+}
@@ -0,0 +1,51 @@
+package bridge
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfirmationStrategy(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		strategy string
+		want     string
+		fail     bool
+	}{
+		{name: "default depth", strategy: "", want: ConfirmByDepth},
+		{name: "depth", strategy: "depth", want: ConfirmByDepth},
+		{name: "safe", strategy: "safe", want: ConfirmBySafe},
+		{name: "finalized", strategy: "finalized", want: ConfirmByFinalized},
+		{name: "instant", strategy: "instant", want: ConfirmInstant},
+		{name: "unknown", strategy: "latest", fail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseConfirmationStrategy(tc.strategy)
+			if tc.fail {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestConfirmedBlockAt(t *testing.T) {
+	head := big.NewInt(100)
+
+	sc := SenderChain{confirmation: ConfirmByDepth, blockConfirmations: big.NewInt(10)}
+	confirmed, err := sc.confirmedBlockAt(head)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), confirmed.Int64())
+
+	sc.confirmation = ConfirmInstant
+	confirmed, err = sc.confirmedBlockAt(head)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), confirmed.Int64())
+	require.NotSame(t, head, confirmed)
+}
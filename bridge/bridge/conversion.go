@@ -0,0 +1,195 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store/mariadb"
+	"database/sql"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 환산 시 나누어 떨어지지 않는 값의 처리 방식
+const (
+	RoundDown    = "down"    // 버림 (기본값)
+	RoundUp      = "up"      // 올림
+	RoundNearest = "nearest" // 반올림
+)
+
+const DefaultDecimals = 18
+
+// conversion은 예치된 BERS 양을 지급할 토큰 양으로 환산합니다.
+// 토큰 양 = BERS 양 * numerator / denominator * 10^destinationDecimals / 10^sourceDecimals
+type conversion struct {
+	numerator   *big.Int
+	denominator *big.Int
+	rounding    string
+}
+
+// newConversion은 config의 환율과 소수점 자리수로 conversion을 생성합니다.
+// 설정되지 않은 값은 1:1 환율과 18 자리수를 사용합니다.
+func newConversion(chainCfg *config.RawChainConfig) (*conversion, error) {
+	numerator, err := parsePositiveInt(chainCfg.RateNumerator, big.NewInt(1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rateNumerator: %w", err)
+	}
+	denominator, err := parsePositiveInt(chainCfg.RateDenominator, big.NewInt(1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rateDenominator: %w", err)
+	}
+	sourceDecimals, err := parseDecimals(chainCfg.SourceDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sourceDecimals: %w", err)
+	}
+	destinationDecimals, err := parseDecimals(chainCfg.DestinationDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destinationDecimals: %w", err)
+	}
+
+	rounding := chainCfg.Rounding
+	switch rounding {
+	case "":
+		rounding = RoundDown
+	case RoundDown, RoundUp, RoundNearest:
+	default:
+		return nil, fmt.Errorf("unknown rounding mode: %s", rounding)
+	}
+
+	// 소수점 자리수의 차이는 환율에 포함시킨다.
+	if destinationDecimals > sourceDecimals {
+		numerator.Mul(numerator, pow10(destinationDecimals-sourceDecimals))
+	} else {
+		denominator.Mul(denominator, pow10(sourceDecimals-destinationDecimals))
+	}
+
+	return &conversion{
+		numerator:   numerator,
+		denominator: denominator,
+		rounding:    rounding,
+	}, nil
+}
+
+// convert는 예치된 BERS 양을 지급할 토큰 양으로 환산합니다.
+func (c *conversion) convert(amount *big.Int) *big.Int {
+	n := new(big.Int).Mul(amount, c.numerator)
+	q, r := new(big.Int).QuoRem(n, c.denominator, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	switch c.rounding {
+	case RoundUp:
+		q.Add(q, big.NewInt(1))
+	case RoundNearest:
+		if new(big.Int).Lsh(r, 1).Cmp(c.denominator) >= 0 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// convertLimit은 설정되지 않은 한도인 nil은 그대로 두고 한도를 환산합니다.
+func (c *conversion) convertLimit(limit *big.Int) *big.Int {
+	if limit == nil {
+		return nil
+	}
+	return c.convert(limit)
+}
+
+// inverse는 토큰 양을 BERS 양으로 환산하는 역방향 conversion을 반환합니다.
+// bridge가 반환받은 토큰보다 많은 BERS를 지급하지 않도록 나누어 떨어지지 않는 값은 항상 버립니다.
+func (c *conversion) inverse() *conversion {
+	return &conversion{
+		numerator:   c.denominator,
+		denominator: c.numerator,
+		rounding:    RoundDown,
+	}
+}
+
+// quote는 deposit의 예치액, 환산액, 수수료와 지급액입니다.
+type quote struct {
+	deposit *big.Int // 예치된 BERS 양
+	gross   *big.Int // 환산된 토큰 양
+	fee     *big.Int // bridge 수수료
+	amount  *big.Int // 수수료를 제외하고 지급할 토큰 양
+}
+
+// quote는 deposit에 대해 수수료를 제외하고 지급할 토큰 양을 계산합니다.
+// 지급액이 0 이하라면 deposit은 지급할 수 없습니다.
+func (r *ReceiverChain) quote(m message.DepositMessage) quote {
+	gross := r.conversion.convert(m.Amount)
+	fee := r.fee.charge(gross)
+	return quote{
+		deposit: m.Amount,
+		gross:   gross,
+		fee:     fee,
+		amount:  new(big.Int).Sub(gross, fee),
+	}
+}
+
+// submittedQuote는 deposit을 submitted 상태로 변경할 때 queue에 저장한 환산액, 수수료, 지급액을 반환합니다.
+// 저장된 값이 없다면 false를 반환합니다.
+func submittedQuote(row mariadb.BersDepositQueue) (quote, bool) {
+	if !row.GrossAmount.Valid || !row.FeeAmount.Valid || !row.PayoutAmount.Valid {
+		return quote{}, false
+	}
+	deposit, ok1 := new(big.Int).SetString(row.Amount, 10)
+	gross, ok2 := new(big.Int).SetString(row.GrossAmount.String, 10)
+	fee, ok3 := new(big.Int).SetString(row.FeeAmount.String, 10)
+	amount, ok4 := new(big.Int).SetString(row.PayoutAmount.String, 10)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return quote{}, false
+	}
+	return quote{deposit: deposit, gross: gross, fee: fee, amount: amount}, true
+}
+
+// submittedParams는 서명된 트랜잭션의 hash, nonce와 지급할 양을 deposit queue에 저장하기 위한 인자를 반환합니다.
+func (q quote) submittedParams(senderTxHash string, hash common.Hash, nonce uint64) mariadb.MarkDepositSubmittedParams {
+	return mariadb.MarkDepositSubmittedParams{
+		ReceiverTxHash: sql.NullString{String: hash.Hex(), Valid: true},
+		ReceiverNonce:  sql.NullInt64{Int64: int64(nonce), Valid: true},
+		GrossAmount:    sql.NullString{String: q.gross.String(), Valid: true},
+		FeeAmount:      sql.NullString{String: q.fee.String(), Valid: true},
+		PayoutAmount:   sql.NullString{String: q.amount.String(), Valid: true},
+		SenderTxHash:   senderTxHash,
+	}
+}
+
+// legacyAmount는 swap history의 amount 컬럼에 저장할 예치액을 이전 버전과 같은 최소 단위의 정수로 반환합니다.
+// bigint 범위를 넘는다면 최대값으로 저장하며, 정확한 양은 deposit_amount 컬럼에 저장됩니다.
+func legacyAmount(deposit *big.Int) int64 {
+	if !deposit.IsInt64() {
+		return math.MaxInt64
+	}
+	return deposit.Int64()
+}
+
+func parsePositiveInt(value string, def *big.Int) (*big.Int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok || n.Sign() <= 0 {
+		return nil, fmt.Errorf("must be a positive integer: %s", value)
+	}
+	return n, nil
+}
+
+func parseDecimals(value string) (int, error) {
+	if value == "" {
+		return DefaultDecimals, nil
+	}
+	d, err := strconv.Atoi(value)
+	if err != nil || d < 0 || d > 77 {
+		return 0, fmt.Errorf("must be between 0 and 77: %s", value)
+	}
+	return d, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
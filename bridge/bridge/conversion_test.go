@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/store/mariadb"
+	"database/sql"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversion(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		cfg    config.RawChainConfig
+		amount int64
+		want   int64
+	}{
+		{name: "default", cfg: config.RawChainConfig{}, amount: 12345, want: 12345},
+		{name: "rate", cfg: config.RawChainConfig{RateNumerator: "3", RateDenominator: "2"}, amount: 100, want: 150},
+		{name: "redenominate", cfg: config.RawChainConfig{SourceDecimals: "18", DestinationDecimals: "6"}, amount: 2500000000000, want: 2},
+		{name: "round up", cfg: config.RawChainConfig{SourceDecimals: "18", DestinationDecimals: "6", Rounding: RoundUp}, amount: 2500000000000, want: 3},
+		{name: "round nearest", cfg: config.RawChainConfig{SourceDecimals: "18", DestinationDecimals: "6", Rounding: RoundNearest}, amount: 2500000000000, want: 3},
+		{name: "round nearest down", cfg: config.RawChainConfig{SourceDecimals: "18", DestinationDecimals: "6", Rounding: RoundNearest}, amount: 2400000000000, want: 2},
+		{name: "more decimals", cfg: config.RawChainConfig{SourceDecimals: "6", DestinationDecimals: "18", RateNumerator: "1", RateDenominator: "1000"}, amount: 5, want: 5000000000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newConversion(&tc.cfg)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, c.convert(big.NewInt(tc.amount)).Int64())
+		})
+	}
+
+	_, err := newConversion(&config.RawChainConfig{RateDenominator: "0"})
+	require.Error(t, err)
+	_, err = newConversion(&config.RawChainConfig{Rounding: "banker"})
+	require.Error(t, err)
+}
+
+func TestConversionInverse(t *testing.T) {
+	c, err := newConversion(&config.RawChainConfig{RateNumerator: "3", RateDenominator: "2", SourceDecimals: "18", DestinationDecimals: "6", Rounding: RoundUp})
+	require.NoError(t, err)
+
+	inv := c.inverse()
+	// 1.5 토큰 = 1 BERS
+	require.Equal(t, "1000000000000000000", inv.convert(big.NewInt(1500000)).String())
+	// 나누어 떨어지지 않는 값은 rounding과 관계없이 버린다.
+	require.Equal(t, "666666666666", inv.convert(big.NewInt(1)).String())
+	require.Equal(t, RoundUp, c.rounding)
+}
+
+// 전송할 때 저장한 지급액을 그대로 다시 읽는가?
+func TestSubmittedQuote(t *testing.T) {
+	q := quote{
+		deposit: big.NewInt(1000),
+		gross:   big.NewInt(1500),
+		fee:     big.NewInt(15),
+		amount:  big.NewInt(1485),
+	}
+	arg := q.submittedParams("0x11", common.HexToHash("0xa0"), 7)
+	require.Equal(t, int64(7), arg.ReceiverNonce.Int64)
+
+	row := mariadb.BersDepositQueue{
+		SenderTxHash: arg.SenderTxHash,
+		Amount:       "1000",
+		GrossAmount:  arg.GrossAmount,
+		FeeAmount:    arg.FeeAmount,
+		PayoutAmount: arg.PayoutAmount,
+	}
+	got, ok := submittedQuote(row)
+	require.True(t, ok)
+	require.Equal(t, q, got)
+
+	// 지급액을 저장하기 전에 submitted 상태가 된 deposit
+	row.PayoutAmount = sql.NullString{}
+	_, ok = submittedQuote(row)
+	require.False(t, ok)
+}
+
+func TestLegacyAmount(t *testing.T) {
+	// 이전 버전과 같이 예치액을 최소 단위로 저장한다.
+	require.Equal(t, int64(1e17), legacyAmount(big.NewInt(1e17)))
+	require.Equal(t, int64(math.MaxInt64), legacyAmount(big.NewInt(math.MaxInt64)))
+	require.Equal(t, int64(math.MaxInt64), legacyAmount(new(big.Int).Mul(big.NewInt(12), pow10(18))))
+}
@@ -0,0 +1,252 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"berith-swap/bridge/transaction"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var maxFeeBps = big.NewInt(10000)
+
+// ReasonFeeExceedsDeposit는 수수료가 환산된 토큰 양 이상이라 지급할 토큰이 없어 보류한 deposit의 사유입니다.
+const ReasonFeeExceedsDeposit = "deposit amount does not cover the fee"
+
+// feeModel은 지급할 토큰 양에서 공제할 bridge 수수료를 계산합니다.
+// 수수료 = feeFlat + 환산된 토큰 양 * feeBps / 10000 이며 feeMin과 feeMax 사이로 제한됩니다.
+type feeModel struct {
+	flat *big.Int
+	bps  *big.Int
+	min  *big.Int
+	max  *big.Int // 상한이 없다면 nil
+}
+
+// newFeeModel은 config로부터 수수료 모델을 생성합니다. 설정되지 않은 값은 0으로 간주합니다.
+func newFeeModel(chainCfg *config.RawChainConfig) (*feeModel, error) {
+	flat, err := parseNonNegativeInt(chainCfg.FeeFlat)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feeFlat: %w", err)
+	}
+	bps, err := parseNonNegativeInt(chainCfg.FeeBps)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feeBps: %w", err)
+	}
+	if bps.Cmp(maxFeeBps) > 0 {
+		return nil, fmt.Errorf("invalid feeBps: must not exceed %s", maxFeeBps.String())
+	}
+	min, err := parseNonNegativeInt(chainCfg.FeeMin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feeMin: %w", err)
+	}
+
+	f := &feeModel{flat: flat, bps: bps, min: min}
+	if chainCfg.FeeMax != "" {
+		max, err := parseNonNegativeInt(chainCfg.FeeMax)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feeMax: %w", err)
+		}
+		if max.Cmp(min) < 0 {
+			return nil, fmt.Errorf("invalid feeMax: must not be less than feeMin")
+		}
+		f.max = max
+	}
+	return f, nil
+}
+
+// charge는 환산된 토큰 양에 대한 수수료를 반환합니다.
+func (f *feeModel) charge(gross *big.Int) *big.Int {
+	fee := new(big.Int).Mul(gross, f.bps)
+	fee.Quo(fee, maxFeeBps)
+	fee.Add(fee, f.flat)
+	if fee.Cmp(f.min) < 0 {
+		fee.Set(f.min)
+	}
+	if f.max != nil && fee.Cmp(f.max) > 0 {
+		fee.Set(f.max)
+	}
+	return fee
+}
+
+// holdUncoveredFee는 quote q의 수수료가 환산된 토큰 양 이상이라 지급할 토큰이 없는 deposit을 held 상태로 보류하고 true를 반환합니다.
+// 보류된 deposit은 운영자가 거절하면 예치한 BERS를 환불받을 수 있습니다.
+func (r *ReceiverChain) holdUncoveredFee(m message.DepositMessage, q quote) (bool, error) {
+	if q.amount.Sign() > 0 {
+		return false, nil
+	}
+	reason := fmt.Sprintf("%s. value:%s, fee:%s", ReasonFeeExceedsDeposit, m.Amount.String(), q.fee.String())
+	return true, r.holdDeposit(m, reason)
+}
+
+// setTreasury는 수수료를 sweep 할 treasury 주소를 설정합니다.
+func (r *ReceiverChain) setTreasury(chainCfg *config.RawChainConfig) {
+	if chainCfg.TreasuryAddress == "" {
+		return
+	}
+	if !common.IsHexAddress(chainCfg.TreasuryAddress) {
+		r.c.Logger.Panic().Msgf("invalid treasuryAddress:%s", chainCfg.TreasuryAddress)
+	}
+	treasury := common.HexToAddress(chainCfg.TreasuryAddress)
+	r.treasury = &treasury
+}
+
+// SweepFees는 ReceiverChain에 누적된 수수료를 treasury 주소로 전송합니다.
+// Receiver와 같은 계정으로 트랜잭션을 서명하므로 Bridge가 실행 중이라면 nonce 충돌을 막기 위해 sweep 하지 않습니다.
+func (b *Bridge) SweepFees() error {
+	err := b.lockSigners()
+	if err != nil {
+		return fmt.Errorf("cannot sweep fees while bridge is running. stop the bridge and retry. err:%w", err)
+	}
+	defer b.unlockSigners()
+	return b.rc.sweepFees()
+}
+
+// sweepFees는 swap history에 누적된 수수료 중 아직 sweep 되지 않은 토큰을 treasury 주소로 전송합니다.
+// mint 방식이라면 수수료는 발행되지 않았으므로 treasury 주소에 mint 합니다.
+// 이전 sweep의 결과를 먼저 확인하며, 결과를 확인할 수 없는 sweep이 남아있다면 sweep 하지 않습니다.
+func (r *ReceiverChain) sweepFees() error {
+	if r.treasury == nil {
+		return fmt.Errorf("treasuryAddress is not set")
+	}
+
+	err := r.reconcileFeeSweeps()
+	if err != nil {
+		return err
+	}
+
+	balance, err := r.store.GetFeeBalance(context.Background())
+	if err != nil {
+		return fmt.Errorf("cannot get fee balance. err:%w", err)
+	}
+	amount, ok := new(big.Int).SetString(balance, 10)
+	if !ok {
+		return fmt.Errorf("invalid fee balance:%s", balance)
+	}
+	if amount.Sign() <= 0 {
+		r.c.Logger.Info().Msg("no fees to sweep")
+		return nil
+	}
+
+	// 전송하기 전에 한 DB 트랜잭션에서 남은 수수료를 다시 확인하고 sweep을 기록하여 같은 수수료가 두 번 sweep 되지 않도록 한다.
+	opts := transaction.TransactOptions{
+		GasLimit: r.c.GasLimit.Uint64(),
+		OnSigned: func(hash common.Hash, nonce uint64) error {
+			return r.store.CreateFeeSweepTx(context.Background(), mariadb.CreateFeeSweepParams{
+				TxHash:          hash.Hex(),
+				TreasuryAddress: r.treasury.Hex(),
+				Amount:          amount.String(),
+				Nonce:           sql.NullInt64{Int64: int64(nonce), Valid: true},
+			})
+		},
+	}
+	txHash, err := r.strategy.submit(*r.treasury, amount, opts)
+	if err != nil {
+		return fmt.Errorf("cannot submit fee sweep. err:%w", err)
+	}
+	r.c.Logger.Info().Msgf("submitted fee sweep. treasury:%s, amount:%s, Tx Hash:%s", r.treasury.Hex(), amount.String(), txHash.Hex())
+
+	rec, err := r.erc20Contract.WaitAndReturnTxReceipt(txHash)
+	if err != nil && (rec == nil || rec.Status != types.ReceiptStatusFailed) {
+		return fmt.Errorf("cannot get fee sweep receipt. reconcile on next sweep. tx:%s, err:%w", txHash.Hex(), err)
+	}
+	err = r.resolveFeeSweep(*txHash, rec)
+	if err != nil {
+		return err
+	}
+	if rec.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("fee sweep reverted. tx:%s", txHash.Hex())
+	}
+	r.c.Logger.Info().Msgf("swept fees to treasury. amount:%s, Tx Hash:%s", amount.String(), txHash.Hex())
+	return nil
+}
+
+// reconcileFeeSweeps는 submitted 상태로 남은 수수료 sweep을 체인의 상태와 비교하여 정리합니다.
+// 트랜잭션이 체인과 mempool에 존재하지 않고 해당 nonce를 다른 트랜잭션이 사용했거나 DroppedTransferTimeout이 지났다면
+// failed로 변경하여 수수료를 다시 sweep 할 수 있도록 합니다. 아직 결과를 알 수 없는 sweep이 있다면 ErrFeeSweepPending을 반환합니다.
+func (r *ReceiverChain) reconcileFeeSweeps() error {
+	sweeps, err := r.store.ListFeeSweepsByStatus(context.Background(), store.FeeSweepSubmitted)
+	if err != nil {
+		return fmt.Errorf("cannot get submitted fee sweeps. err:%w", err)
+	}
+
+	for _, sweep := range sweeps {
+		hash := common.HexToHash(sweep.TxHash)
+		rec, err := r.c.EvmClient.TransactionReceipt(context.Background(), hash)
+		if err == nil {
+			if err := r.resolveFeeSweep(hash, rec); err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("cannot get receipt of fee sweep. tx:%s, err:%w", hash.Hex(), err)
+		}
+
+		_, _, err = r.c.EvmClient.GetTransactionByHash(hash)
+		if err == nil {
+			return fmt.Errorf("%w. fee sweep is pending. tx:%s", store.ErrFeeSweepPending, hash.Hex())
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("cannot get fee sweep transaction. tx:%s, err:%w", hash.Hex(), err)
+		}
+
+		nonce, err := r.c.EvmClient.NonceAt(context.Background(), r.c.EvmClient.From(), nil)
+		if err != nil {
+			return fmt.Errorf("cannot get account nonce. err:%w", err)
+		}
+		switch {
+		case sweep.Nonce.Valid && uint64(sweep.Nonce.Int64) < nonce:
+			r.c.Logger.Warn().Msgf("nonce of fee sweep was used by another transaction. tx:%s, nonce:%d", hash.Hex(), sweep.Nonce.Int64)
+		case sweep.CreatedAt.Valid && time.Since(sweep.CreatedAt.Time) >= DroppedTransferTimeout:
+			r.c.Logger.Warn().Msgf("fee sweep was dropped from mempool. tx:%s, submitted at:%s", hash.Hex(), sweep.CreatedAt.Time)
+			r.c.EvmClient.ResetNonce()
+		default:
+			return fmt.Errorf("%w. fee sweep not found. retry later. tx:%s", store.ErrFeeSweepPending, hash.Hex())
+		}
+		if err := r.updateFeeSweepStatus(hash, store.FeeSweepFailed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveFeeSweep은 receipt에 따라 수수료 sweep을 confirmed 또는 failed 상태로 변경합니다.
+func (r *ReceiverChain) resolveFeeSweep(hash common.Hash, rec *types.Receipt) error {
+	status := store.FeeSweepConfirmed
+	if rec.Status != types.ReceiptStatusSuccessful {
+		status = store.FeeSweepFailed
+	}
+	return r.updateFeeSweepStatus(hash, status)
+}
+
+func (r *ReceiverChain) updateFeeSweepStatus(hash common.Hash, status string) error {
+	err := r.store.UpdateFeeSweepStatus(context.Background(), mariadb.UpdateFeeSweepStatusParams{
+		Status: status,
+		TxHash: hash.Hex(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update fee sweep status. tx:%s, status:%s, err:%w", hash.Hex(), status, err)
+	}
+	return nil
+}
+
+func parseNonNegativeInt(value string) (*big.Int, error) {
+	if value == "" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("must be a non-negative integer: %s", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeModelCharge(t *testing.T) {
+	var testCases = []struct {
+		name  string
+		cfg   config.RawChainConfig
+		gross int64
+		want  int64
+	}{
+		{name: "no fee", cfg: config.RawChainConfig{}, gross: 1000, want: 0},
+		{name: "flat", cfg: config.RawChainConfig{FeeFlat: "10"}, gross: 1000, want: 10},
+		{name: "percentage", cfg: config.RawChainConfig{FeeBps: "30"}, gross: 100000, want: 300},
+		{name: "flat and percentage", cfg: config.RawChainConfig{FeeFlat: "10", FeeBps: "30"}, gross: 100000, want: 310},
+		{name: "min", cfg: config.RawChainConfig{FeeBps: "30", FeeMin: "50"}, gross: 1000, want: 50},
+		{name: "max", cfg: config.RawChainConfig{FeeBps: "30", FeeMax: "100"}, gross: 100000, want: 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newFeeModel(&tc.cfg)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, f.charge(big.NewInt(tc.gross)).Int64())
+		})
+	}
+
+	_, err := newFeeModel(&config.RawChainConfig{FeeBps: "10001"})
+	require.Error(t, err)
+	_, err = newFeeModel(&config.RawChainConfig{FeeMin: "10", FeeMax: "5"})
+	require.Error(t, err)
+	_, err = newFeeModel(&config.RawChainConfig{FeeFlat: "-1"})
+	require.Error(t, err)
+}
+
+// 수수료가 환산된 토큰 양 이상인 deposit을 invalid가 아닌 held 상태로 보류하여 환불할 수 있도록 하는가?
+func TestHoldUncoveredFee(t *testing.T) {
+	st, db := newTestStore(t)
+	conversion, err := newConversion(&config.RawChainConfig{})
+	require.NoError(t, err)
+	fee, err := newFeeModel(&config.RawChainConfig{FeeFlat: "10"})
+	require.NoError(t, err)
+	r := &ReceiverChain{
+		c:          &chain.Chain{Logger: zerolog.Nop()},
+		store:      st,
+		conversion: conversion,
+		fee:        fee,
+	}
+
+	deposit := func(amount int64) message.DepositMessage {
+		return message.NewDepositMessage(1, common.Hash{}, uint64(amount), common.HexToAddress("0x1"), common.HexToAddress("0x2"), big.NewInt(amount), common.BigToHash(big.NewInt(amount)).Hex())
+	}
+
+	covered := deposit(11)
+	parked, err := r.holdUncoveredFee(covered, r.quote(covered))
+	require.NoError(t, err)
+	require.False(t, parked)
+	require.Empty(t, db.executed("HoldDeposit"))
+
+	// 지급 전에 보류하므로 토큰을 전송하지 않는다.
+	uncovered := deposit(10)
+	require.NoError(t, r.payout(uncovered, true))
+
+	held := db.executed("HoldDeposit")
+	require.Len(t, held, 1)
+	require.Contains(t, held[0][0], ReasonFeeExceedsDeposit)
+	require.Equal(t, uncovered.SenderTxHash, held[0][1])
+	require.Len(t, db.executed("CreateSwapReview"), 1)
+	require.Empty(t, db.executed("UpdateDepositStatus"))
+}
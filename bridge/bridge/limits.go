@@ -0,0 +1,186 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// swapLimits는 deposit 하나의 최소, 최대 예치액과 최근 24시간 동안의 주소별, 전체 예치액 한도입니다.
+// 모든 한도는 예치된 BERS 양을 기준으로 하며, 설정되지 않은 한도는 nil로 제한하지 않습니다.
+type swapLimits struct {
+	min        *big.Int
+	max        *big.Int
+	addressCap *big.Int // sender 주소별 24시간 한도
+	globalCap  *big.Int // 전체 24시간 한도
+}
+
+// newSwapLimits는 config로부터 지급 한도를 생성합니다.
+func newSwapLimits(chainCfg *config.RawChainConfig) (*swapLimits, error) {
+	var (
+		l   swapLimits
+		err error
+	)
+	if l.min, err = parseLimit(chainCfg.MinSwapAmount); err != nil {
+		return nil, fmt.Errorf("invalid minSwapAmount: %w", err)
+	}
+	if l.max, err = parseLimit(chainCfg.MaxSwapAmount); err != nil {
+		return nil, fmt.Errorf("invalid maxSwapAmount: %w", err)
+	}
+	if l.min != nil && l.max != nil && l.max.Cmp(l.min) < 0 {
+		return nil, fmt.Errorf("invalid maxSwapAmount: must not be less than minSwapAmount")
+	}
+	if l.addressCap, err = parseLimit(chainCfg.DailyAddressLimit); err != nil {
+		return nil, fmt.Errorf("invalid dailyAddressLimit: %w", err)
+	}
+	if l.globalCap, err = parseLimit(chainCfg.DailyGlobalLimit); err != nil {
+		return nil, fmt.Errorf("invalid dailyGlobalLimit: %w", err)
+	}
+	return &l, nil
+}
+
+// convert는 BERS 양으로 설정된 한도를 c로 환산한 한도를 반환합니다.
+func (l *swapLimits) convert(c *conversion) *swapLimits {
+	return &swapLimits{
+		min:        c.convertLimit(l.min),
+		max:        c.convertLimit(l.max),
+		addressCap: c.convertLimit(l.addressCap),
+		globalCap:  c.convertLimit(l.globalCap),
+	}
+}
+
+// check는 이미 사용된 한도에 amount를 더했을 때 한도를 넘는다면 보류 사유를 반환합니다.
+// 한도 안이라면 빈 문자열을 반환합니다.
+func (l *swapLimits) check(amount, senderUsed, globalUsed *big.Int) string {
+	if l.min != nil && amount.Cmp(l.min) < 0 {
+		return fmt.Sprintf("deposit amount below minimum. amount:%s, min:%s", amount.String(), l.min.String())
+	}
+	if l.max != nil && amount.Cmp(l.max) > 0 {
+		return fmt.Sprintf("deposit amount above maximum. amount:%s, max:%s", amount.String(), l.max.String())
+	}
+	if l.addressCap != nil && new(big.Int).Add(senderUsed, amount).Cmp(l.addressCap) > 0 {
+		return fmt.Sprintf("daily address limit exceeded. amount:%s, used:%s, limit:%s", amount.String(), senderUsed.String(), l.addressCap.String())
+	}
+	if l.globalCap != nil && new(big.Int).Add(globalUsed, amount).Cmp(l.globalCap) > 0 {
+		return fmt.Sprintf("daily global limit exceeded. amount:%s, used:%s, limit:%s", amount.String(), globalUsed.String(), l.globalCap.String())
+	}
+	return ""
+}
+
+// limitUsage는 한 방향의 swap에서 최근 24시간 동안 지급되었거나 지급 중인 예치액입니다.
+type limitUsage struct {
+	store     *store.Store
+	limits    *swapLimits
+	direction string
+	pending   []message.DepositMessage // 아직 전송되지 않았지만 지급하기로 한 deposit
+	global    *big.Int
+	bySender  map[common.Address]*big.Int // 조회한 sender 주소의 예치액
+}
+
+// loadLimitUsage는 최근 24시간 동안 submitted, confirmed 상태가 된 deposit과
+// pending에 모여 아직 전송되지 않은 deposit의 예치액 합계를 조회합니다.
+func loadLimitUsage(st *store.Store, limits *swapLimits, direction string, pending []message.DepositMessage) (*limitUsage, error) {
+	u := &limitUsage{
+		store:     st,
+		limits:    limits,
+		direction: direction,
+		pending:   pending,
+		global:    new(big.Int),
+		bySender:  make(map[common.Address]*big.Int),
+	}
+	if limits.globalCap != nil {
+		volume, err := st.GetDepositVolume(context.Background(), direction)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get daily deposit volume. err:%w", err)
+		}
+		if err := setVolume(u.global, volume); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range pending {
+		u.global.Add(u.global, m.Amount)
+	}
+	return u, nil
+}
+
+// loadLimitUsage는 batch에 모인 deposit을 포함하여 Klaytn 토큰 지급의 한도 사용량을 조회합니다.
+func (r *ReceiverChain) loadLimitUsage() (*limitUsage, error) {
+	return loadLimitUsage(r.store, r.limits, store.DirectionForward, r.batch)
+}
+
+// senderUsage는 sender 주소의 최근 24시간 예치액을 반환합니다. 한 번 조회한 주소는 다시 조회하지 않습니다.
+func (u *limitUsage) senderUsage(sender common.Address) (*big.Int, error) {
+	if used, ok := u.bySender[sender]; ok {
+		return used, nil
+	}
+
+	used := new(big.Int)
+	if u.limits.addressCap != nil {
+		volume, err := u.store.GetDepositVolumeBySender(context.Background(), mariadb.GetDepositVolumeBySenderParams{
+			Direction:     u.direction,
+			SenderAddress: sender.Hex(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot get daily deposit volume. sender:%s, err:%w", sender.Hex(), err)
+		}
+		if err := setVolume(used, volume); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range u.pending {
+		if m.Sender == sender {
+			used.Add(used, m.Amount)
+		}
+	}
+	u.bySender[sender] = used
+	return used, nil
+}
+
+// check는 deposit이 한도 안이라면 사용량에 예치액을 더하고, 한도를 넘는다면 보류 사유를 반환합니다.
+func (u *limitUsage) check(m message.DepositMessage) (string, error) {
+	used, err := u.senderUsage(m.Sender)
+	if err != nil {
+		return "", err
+	}
+	reason := u.limits.check(m.Amount, used, u.global)
+	if reason != "" {
+		return reason, nil
+	}
+	used.Add(used, m.Amount)
+	u.global.Add(u.global, m.Amount)
+	return "", nil
+}
+
+// holdDeposit은 deposit을 수동 검토 대상으로 기록하고 queue에서 held 상태로 보류합니다.
+func (r *ReceiverChain) holdDeposit(m message.DepositMessage, reason string) error {
+	err := r.store.HoldDepositTx(context.Background(), mariadb.CreateSwapReviewParams{
+		SenderTxHash: m.SenderTxHash,
+		Reason:       reason,
+	})
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msgf("cannot hold deposit. hash:%s", m.SenderTxHash)
+		return err
+	}
+	r.c.Logger.Warn().Msgf("%s. hold deposit for manual review. hash:%s", reason, m.SenderTxHash)
+	return nil
+}
+
+func parseLimit(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return parseNonNegativeInt(value)
+}
+
+func setVolume(n *big.Int, volume string) error {
+	if _, ok := n.SetString(volume, 10); !ok {
+		return fmt.Errorf("invalid deposit volume:%s", volume)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwapLimitsCheck(t *testing.T) {
+	cfg := config.RawChainConfig{
+		MinSwapAmount:     "10",
+		MaxSwapAmount:     "100",
+		DailyAddressLimit: "150",
+		DailyGlobalLimit:  "300",
+	}
+
+	var testCases = []struct {
+		name       string
+		amount     int64
+		senderUsed int64
+		globalUsed int64
+		held       bool
+	}{
+		{name: "within limits", amount: 50, senderUsed: 100, globalUsed: 250, held: false},
+		{name: "below minimum", amount: 9, held: true},
+		{name: "above maximum", amount: 101, held: true},
+		{name: "address limit", amount: 60, senderUsed: 100, globalUsed: 100, held: true},
+		{name: "global limit", amount: 60, senderUsed: 0, globalUsed: 250, held: true},
+	}
+
+	l, err := newSwapLimits(&cfg)
+	require.NoError(t, err)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason := l.check(big.NewInt(tc.amount), big.NewInt(tc.senderUsed), big.NewInt(tc.globalUsed))
+			require.Equal(t, tc.held, reason != "", reason)
+		})
+	}
+
+	// 설정되지 않은 한도는 제한하지 않는다.
+	l, err = newSwapLimits(&config.RawChainConfig{})
+	require.NoError(t, err)
+	require.Empty(t, l.check(big.NewInt(1e18), big.NewInt(1e18), big.NewInt(1e18)))
+
+	_, err = newSwapLimits(&config.RawChainConfig{MinSwapAmount: "100", MaxSwapAmount: "10"})
+	require.Error(t, err)
+}
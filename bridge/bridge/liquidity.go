@@ -0,0 +1,128 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/util"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	errInsufficientLiquidity = errors.New("insufficient receiver balance")
+	// 토큰 잔액 부족은 더 적은 지급액의 deposit을 처리할 수 있지만, 가스비 잔액 부족은 어떤 deposit도 처리할 수 없다.
+	errInsufficientToken = fmt.Errorf("%w. token", errInsufficientLiquidity)
+	errInsufficientGas   = fmt.Errorf("%w. gas", errInsufficientLiquidity)
+)
+
+// liquidity는 Receiver 계정이 지급에 사용할 수 있는 토큰과 가스비 잔액입니다.
+type liquidity struct {
+	token   *big.Int // 처리 중인 deposit의 지급액을 제외한 토큰 잔액. mint 방식이라면 nil
+	gas     *big.Int // 처리 중인 트랜잭션의 가스비를 제외한 native coin 잔액
+	gasCost *big.Int // 트랜잭션 하나에 필요한 최대 가스비
+}
+
+// take는 amount 만큼의 토큰과 txs 개의 트랜잭션 가스비를 지급할 수 있다면 잔액에서 차감합니다.
+// 토큰 잔액이 nil이라면 토큰 잔액은 확인하지 않습니다.
+// 토큰 잔액이 부족하다면 errInsufficientToken을, 가스비 잔액이 부족하다면 errInsufficientGas를 반환합니다.
+func (l *liquidity) take(amount *big.Int, txs int) error {
+	gas := new(big.Int).Mul(l.gasCost, big.NewInt(int64(txs)))
+	if l.token != nil && l.token.Cmp(amount) < 0 {
+		return fmt.Errorf("%w balance:%s, required:%s", errInsufficientToken, l.token.String(), amount.String())
+	}
+	if l.gas.Cmp(gas) < 0 {
+		return fmt.Errorf("%w balance:%s, required:%s", errInsufficientGas, l.gas.String(), gas.String())
+	}
+	if l.token != nil {
+		l.token.Sub(l.token, amount)
+	}
+	l.gas.Sub(l.gas, gas)
+	return nil
+}
+
+// setLowBalanceThresholds는 잔액 부족 경고를 위한 토큰과 가스비 잔액의 기준을 설정합니다.
+func (r *ReceiverChain) setLowBalanceThresholds(chainCfg *config.RawChainConfig) {
+	if chainCfg.LowTokenBalance != "" {
+		threshold, err := util.StringToBig(chainCfg.LowTokenBalance, 10)
+		if err != nil {
+			r.c.Logger.Panic().Err(err).Msgf("invalid lowTokenBalance:%s", chainCfg.LowTokenBalance)
+		}
+		r.lowTokenBalance = threshold
+	}
+	if chainCfg.LowGasBalance != "" {
+		threshold, err := util.StringToBig(chainCfg.LowGasBalance, 10)
+		if err != nil {
+			r.c.Logger.Panic().Err(err).Msgf("invalid lowGasBalance:%s", chainCfg.LowGasBalance)
+		}
+		r.lowGasBalance = threshold
+	}
+}
+
+// loadLiquidity는 Receiver 계정의 토큰과 native coin 잔액을 조회하고,
+// 처리 중인 deposit의 지급액과 트랜잭션의 가스비를 제외한 잔액을 반환합니다.
+// 토큰을 mint 하여 지급한다면 토큰 잔액은 확인하지 않습니다.
+func (r *ReceiverChain) loadLiquidity() (*liquidity, error) {
+	from := r.c.EvmClient.From()
+	token, err := r.strategy.balance(from)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get token balance. err:%w", err)
+	}
+	gas, err := r.c.EvmClient.BalanceAt(context.Background(), from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get gas balance. err:%w", err)
+	}
+	gasPrice, err := r.c.EvmClient.SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get gas price. err:%w", err)
+	}
+	r.alertLowBalance(token, gas)
+
+	gasCost := new(big.Int).Mul(r.c.GasLimit, gasPrice)
+	pendingGas := new(big.Int).Mul(gasCost, big.NewInt(int64(len(r.slots))))
+	liq := &liquidity{
+		gas:     new(big.Int).Sub(gas, pendingGas),
+		gasCost: gasCost,
+	}
+	if token != nil {
+		liq.token = new(big.Int).Sub(token, r.reservedAmount())
+	}
+	return liq, nil
+}
+
+// alertLowBalance는 잔액이 config에 설정된 기준보다 낮아지거나 회복되면 로그를 남깁니다.
+func (r *ReceiverChain) alertLowBalance(token, gas *big.Int) {
+	lowToken := r.lowTokenBalance != nil && token != nil && token.Cmp(r.lowTokenBalance) < 0
+	if lowToken != r.lowToken {
+		if lowToken {
+			r.c.Logger.Warn().Msgf("low token balance. balance:%s, threshold:%s", token.String(), r.lowTokenBalance.String())
+		} else {
+			r.c.Logger.Info().Msgf("token balance recovered. balance:%s", token.String())
+		}
+		r.lowToken = lowToken
+	}
+
+	lowGas := r.lowGasBalance != nil && gas.Cmp(r.lowGasBalance) < 0
+	if lowGas != r.lowGas {
+		if lowGas {
+			r.c.Logger.Warn().Msgf("low gas balance. balance:%s, threshold:%s", gas.String(), r.lowGasBalance.String())
+		} else {
+			r.c.Logger.Info().Msgf("gas balance recovered. balance:%s", gas.String())
+		}
+		r.lowGas = lowGas
+	}
+}
+
+// setLiquidityShort는 잔액 부족으로 지급이 멈추거나 다시 시작될 때 로그를 남깁니다.
+func (r *ReceiverChain) setLiquidityShort(err error) {
+	short := err != nil
+	if short == r.liquidityShort {
+		return
+	}
+	if short {
+		r.c.Logger.Error().Err(err).Msg("receiver balance is insufficient. hold deposits in queue until refilled")
+	} else {
+		r.c.Logger.Info().Msg("receiver balance refilled. resume payouts")
+	}
+	r.liquidityShort = short
+}
@@ -0,0 +1,47 @@
+package bridge
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiquidityTake(t *testing.T) {
+	liq := &liquidity{
+		token:   big.NewInt(100),
+		gas:     big.NewInt(30),
+		gasCost: big.NewInt(10),
+	}
+
+	require.NoError(t, liq.take(big.NewInt(60), 1))
+	require.Equal(t, int64(40), liq.token.Int64())
+	require.Equal(t, int64(20), liq.gas.Int64())
+
+	// 토큰 잔액이 부족하면 잔액을 차감하지 않는다.
+	err := liq.take(big.NewInt(50), 1)
+	require.ErrorIs(t, err, errInsufficientLiquidity)
+	require.ErrorIs(t, err, errInsufficientToken)
+	require.Equal(t, int64(40), liq.token.Int64())
+
+	// 가스비 잔액이 부족하면 잔액을 차감하지 않는다.
+	err = liq.take(big.NewInt(10), 3)
+	require.ErrorIs(t, err, errInsufficientLiquidity)
+	require.ErrorIs(t, err, errInsufficientGas)
+	require.Equal(t, int64(40), liq.token.Int64())
+	require.Equal(t, int64(20), liq.gas.Int64())
+
+	// batch에 추가되는 deposit은 가스비를 차감하지 않는다.
+	require.NoError(t, liq.take(big.NewInt(40), 0))
+	require.Equal(t, int64(0), liq.token.Int64())
+	require.Equal(t, int64(20), liq.gas.Int64())
+
+	// mint 방식이라면 토큰 잔액은 확인하지 않는다.
+	liq = &liquidity{
+		gas:     big.NewInt(10),
+		gasCost: big.NewInt(10),
+	}
+	require.NoError(t, liq.take(big.NewInt(1000), 1))
+	require.Nil(t, liq.token)
+	require.ErrorIs(t, liq.take(big.NewInt(1000), 1), errInsufficientLiquidity)
+}
@@ -6,12 +6,28 @@ import (
 	"berith-swap/bridge/contract"
 	"berith-swap/bridge/evmgaspricer"
 	"berith-swap/bridge/keypair"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
 	"berith-swap/bridge/transaction"
 	"berith-swap/logger"
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/rs/zerolog"
@@ -81,3 +97,200 @@ func testNewBridgeContract(t *testing.T, chainCfg *config.RawChainConfig) (*cont
 
 	return contract.NewSwapContract(client, common.HexToAddress(chainCfg.SwapAddress), trans, &testLogger), client.From()
 }
+
+// testChainId는 테스트 RPC 서버가 응답하는 chain id입니다.
+var testChainId = big.NewInt(1337)
+
+type testRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newTestRPCClient는 handle이 반환한 결과로 단일 요청과 batch 요청에 응답하는 테스트 RPC 서버에 연결합니다.
+// eth_chainId에는 testChainId로 응답하며, handle이 nil을 반환하면 null로 응답합니다.
+func newTestRPCClient(t *testing.T, handle func(method string, params []json.RawMessage) interface{}) *connection.EvmClient {
+	respond := func(req testRPCRequest) map[string]interface{} {
+		var result interface{}
+		if req.Method == "eth_chainId" {
+			result = (*hexutil.Big)(testChainId)
+		} else {
+			result = handle(req.Method, req.Params)
+		}
+		return map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  result,
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		var resp interface{}
+		if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			var reqs []testRPCRequest
+			require.NoError(t, json.Unmarshal(body, &reqs))
+			resps := make([]map[string]interface{}, len(reqs))
+			for i, req := range reqs {
+				resps[i] = respond(req)
+			}
+			resp = resps
+		} else {
+			var req testRPCRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			resp = respond(req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(srv.Close)
+
+	pk, err := crypto.HexToECDSA(testPrivKey)
+	require.NoError(t, err)
+	testLogger := zerolog.Nop()
+	client, err := connection.NewEvmClient(keypair.NewKeypairFromPrivateKey(pk), srv.URL, &testLogger)
+	require.NoError(t, err)
+	return client
+}
+
+// testDB는 deposit queue와 환불 조회, 변경 쿼리에 응답하는 테스트 DB입니다.
+// GetDeposit, ListDepositsByStatus는 deposits에, ListRefundsByStatus는 refunds에 저장된 row로 응답하고,
+// 그 외의 변경 쿼리는 이름과 인자를 execs에 기록합니다.
+type testDB struct {
+	mu       sync.Mutex
+	deposits map[string]mariadb.BersDepositQueue
+	refunds  []mariadb.BersRefund
+	execs    []testExec
+}
+
+type testExec struct {
+	name string
+	args []driver.Value
+}
+
+// newTestStore는 rows로 응답하는 테스트 DB에 연결된 store를 생성합니다.
+// DB 트랜잭션은 rollback 되더라도 실행된 쿼리를 되돌리지 않습니다.
+func newTestStore(t *testing.T, rows ...mariadb.BersDepositQueue) (*store.Store, *testDB) {
+	db := &testDB{deposits: make(map[string]mariadb.BersDepositQueue)}
+	for _, row := range rows {
+		db.deposits[row.SenderTxHash] = row
+	}
+	conn := sql.OpenDB(db)
+	t.Cleanup(func() { conn.Close() })
+	return store.NewStoreFromDB(conn), db
+}
+
+// executed는 name 쿼리가 실행된 인자를 실행된 순서대로 반환합니다.
+func (db *testDB) executed(name string) [][]driver.Value {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var args [][]driver.Value
+	for _, e := range db.execs {
+		if e.name == name {
+			args = append(args, e.args)
+		}
+	}
+	return args
+}
+
+func (db *testDB) Connect(context.Context) (driver.Conn, error) { return &testConn{db: db}, nil }
+func (db *testDB) Driver() driver.Driver                        { return nil }
+
+type testConn struct {
+	db *testDB
+}
+
+func (c *testConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *testConn) Close() error                        { return nil }
+func (c *testConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *testConn) Commit() error                       { return nil }
+func (c *testConn) Rollback() error                     { return nil }
+
+func (c *testConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	rows := &testRows{columns: 21}
+	switch queryName(query) {
+	case "GetDeposit":
+		if row, ok := c.db.deposits[args[0].Value.(string)]; ok {
+			rows.values = append(rows.values, depositValues(row))
+		}
+	case "ListDepositsByStatus":
+		for _, row := range c.db.deposits {
+			if row.Direction == args[0].Value && row.Status == args[1].Value {
+				rows.values = append(rows.values, depositValues(row))
+			}
+		}
+	case "ListRefundsByStatus":
+		rows.columns = 9
+		for _, refund := range c.db.refunds {
+			if refund.Status == args[0].Value {
+				rows.values = append(rows.values, []driver.Value{
+					refund.SenderTxHash, refund.TxHash, refund.Nonce, refund.SenderAddress, refund.Amount,
+					refund.DepositStatus, refund.Status, nullValue(refund.CreatedAt), nullValue(refund.UpdatedAt),
+				})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected query:%s", queryName(query))
+	}
+	return rows, nil
+}
+
+// depositValues는 bers_deposit_queue의 컬럼 순서대로 row의 값을 반환합니다.
+func depositValues(row mariadb.BersDepositQueue) []driver.Value {
+	return []driver.Value{
+		row.SenderTxHash, row.BlockNumber, row.BlockHash, row.DepositNonce, row.SenderAddress, row.ReceiverAddress,
+		row.Amount, row.Status, nullValue(row.CreatedAt), nullValue(row.UpdatedAt), nullValue(row.ReceiverTxHash),
+		nullValue(row.ReceiverNonce), nullValue(row.Reason), int64(row.RetryCount), nullValue(row.NextRetryAt),
+		nullValue(row.LastError), nullValue(row.ApprovedAt), row.Direction, nullValue(row.GrossAmount),
+		nullValue(row.FeeAmount), nullValue(row.PayoutAmount),
+	}
+}
+
+func (c *testConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.db.execs = append(c.db.execs, testExec{name: queryName(query), args: values})
+	return driver.RowsAffected(1), nil
+}
+
+// queryName은 sqlc가 생성한 쿼리의 이름을 반환합니다.
+func queryName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) < 3 || fields[1] != "name:" {
+		return ""
+	}
+	return fields[2]
+}
+
+func nullValue(v driver.Valuer) driver.Value {
+	value, _ := v.Value()
+	return value
+}
+
+type testRows struct {
+	columns int
+	values  [][]driver.Value
+}
+
+func (r *testRows) Columns() []string {
+	return make([]string, r.columns)
+}
+
+func (r *testRows) Close() error { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
@@ -0,0 +1,116 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/message"
+	"math/big"
+	"sort"
+)
+
+// ReasonRepeatedNonce는 이미 사용된 depositNonce를 가져 보류한 deposit의 사유입니다.
+const ReasonRepeatedNonce = "deposit nonce repeated"
+
+type nonceCheck int
+
+const (
+	nonceInOrder  nonceCheck = iota // 마지막 nonce 다음 순서의 deposit
+	nonceReplayed                   // 이미 탐색한 블록을 다시 탐색하며 발견된 deposit
+	nonceRepeated                   // 이미 사용된 nonce를 가진 다른 deposit
+	nonceSkipped                    // 이전 nonce가 누락된 deposit
+)
+
+// checkDepositNonce는 마지막으로 감지한 depositNonce와 비교하여 m의 depositNonce를 분류합니다.
+func checkDepositNonce(last blockstore.NonceRecord, m message.DepositMessage) nonceCheck {
+	switch {
+	case last.Nonce == 0 || m.DepositNonce == last.Nonce+1:
+		return nonceInOrder
+	case m.DepositNonce > last.Nonce+1:
+		return nonceSkipped
+	case m.DepositNonce == last.Nonce && m.SenderTxHash == last.TxHash:
+		return nonceReplayed
+	case m.DepositNonce < last.Nonce && m.BlockNumber <= last.BlockNumber:
+		return nonceReplayed
+	default:
+		return nonceRepeated
+	}
+}
+
+// verifyNonces는 last 이후에 감지된 msgs의 depositNonce가 누락되거나 중복되지 않았는지 검사하고 마지막 nonce를 반환합니다.
+// 중복된 nonce를 가진 deposit은 held로 분류하여 전송하지 않으며,
+// nonceGapRescan이 설정되어 있다면 누락된 nonce가 포함된 구간을 다시 탐색하여 찾은 deposit을 함께 반환합니다.
+// 반환된 deposit은 quorum 검증을 거쳐야 하며, 마지막 nonce는 deposit이 저장된 뒤에 기록해야 합니다.
+func (s *SenderChain) verifyNonces(msgs []message.DepositMessage, last blockstore.NonceRecord) ([]message.DepositMessage, []heldDeposit, blockstore.NonceRecord) {
+	verified := make([]message.DepositMessage, 0, len(msgs))
+	held := []heldDeposit{}
+	for _, m := range msgs {
+		switch checkDepositNonce(last, m) {
+		case nonceReplayed:
+			s.c.Logger.Debug().Msgf("deposit nonce replayed. nonce:%d, hash:%s", m.DepositNonce, m.SenderTxHash)
+			verified = append(verified, m)
+			continue
+		case nonceRepeated:
+			s.c.Logger.Error().Msgf("deposit nonce repeated. nonce:%d, last:%d, hash:%s", m.DepositNonce, last.Nonce, m.SenderTxHash)
+			held = append(held, heldDeposit{msg: m, reason: ReasonRepeatedNonce})
+			continue
+		case nonceSkipped:
+			s.c.Logger.Error().Msgf("deposit nonce skipped. expected:%d, got:%d, hash:%s", last.Nonce+1, m.DepositNonce, m.SenderTxHash)
+			if s.nonceGapRescan {
+				verified = append(verified, s.rescanNonces(m, last)...)
+			}
+		}
+		verified = append(verified, m)
+		last = blockstore.NonceRecord{Nonce: m.DepositNonce, BlockNumber: m.BlockNumber, TxHash: m.SenderTxHash}
+	}
+	return verified, held, last
+}
+
+// storeNonce는 deposit이 저장된 뒤 마지막으로 감지한 depositNonce를 blockstore에 기록합니다.
+func (s *SenderChain) storeNonce(last blockstore.NonceRecord) {
+	if last == s.lastNonce {
+		return
+	}
+	s.lastNonce = last
+	if err := s.blockStore.StoreNonce(s.lastNonce); err != nil {
+		s.c.Logger.Error().Err(err).Msg("Failed to write deposit nonce to blockstore")
+	}
+}
+
+// rescanNonces는 마지막으로 감지한 deposit의 블록부터 m의 블록까지 다시 탐색하여 누락된 deposit을 찾습니다.
+func (s *SenderChain) rescanNonces(m message.DepositMessage, last blockstore.NonceRecord) []message.DepositMessage {
+	found := []message.DepositMessage{}
+	step := s.blockRange.Uint64()
+	s.c.Logger.Info().Msgf("rescan blocks for missing deposit nonces. from:%d, to:%d", last.BlockNumber, m.BlockNumber)
+
+	for start := last.BlockNumber; start <= m.BlockNumber; start += step {
+		end := start + step - 1
+		if end > m.BlockNumber {
+			end = m.BlockNumber
+		}
+
+		logs, err := s.fetchDepositLogs(new(big.Int).SetUint64(start), new(big.Int).SetUint64(end))
+		if err != nil {
+			s.c.Logger.Error().Err(err).Msg("Failed to rescan missing deposit nonces")
+			return found
+		}
+		msgs, err := s.getDepositMessages(logs)
+		if err != nil {
+			s.c.Logger.Error().Err(err).Msg("Failed to rescan missing deposit nonces")
+			return found
+		}
+		for _, r := range msgs {
+			if r.DepositNonce > last.Nonce && r.DepositNonce < m.DepositNonce {
+				found = append(found, r)
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].DepositNonce < found[j].DepositNonce })
+
+	missing := m.DepositNonce - last.Nonce - 1
+	if uint64(len(found)) < missing {
+		s.c.Logger.Error().Msgf("deposit nonces are still missing after rescan. missing:%d, found:%d", missing, len(found))
+	} else {
+		s.c.Logger.Info().Msgf("recovered missing deposits by rescan. found:%d", len(found))
+	}
+	return found
+}
@@ -0,0 +1,70 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/message"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// depositNonce의 누락과 중복을 구분하는가?
+func TestCheckDepositNonce(t *testing.T) {
+	last := blockstore.NonceRecord{Nonce: 10, BlockNumber: 100, TxHash: "0x10"}
+
+	testCases := []struct {
+		name   string
+		last   blockstore.NonceRecord
+		msg    message.DepositMessage
+		expect nonceCheck
+	}{
+		{
+			name:   "first deposit",
+			last:   blockstore.NonceRecord{},
+			msg:    message.DepositMessage{DepositNonce: 7, BlockNumber: 50, SenderTxHash: "0x07"},
+			expect: nonceInOrder,
+		},
+		{
+			name:   "next nonce",
+			last:   last,
+			msg:    message.DepositMessage{DepositNonce: 11, BlockNumber: 101, SenderTxHash: "0x11"},
+			expect: nonceInOrder,
+		},
+		{
+			name:   "skipped nonce",
+			last:   last,
+			msg:    message.DepositMessage{DepositNonce: 13, BlockNumber: 105, SenderTxHash: "0x13"},
+			expect: nonceSkipped,
+		},
+		{
+			name:   "replay same deposit",
+			last:   last,
+			msg:    message.DepositMessage{DepositNonce: 10, BlockNumber: 100, SenderTxHash: "0x10"},
+			expect: nonceReplayed,
+		},
+		{
+			name:   "replay earlier block",
+			last:   last,
+			msg:    message.DepositMessage{DepositNonce: 8, BlockNumber: 90, SenderTxHash: "0x08"},
+			expect: nonceReplayed,
+		},
+		{
+			name:   "same nonce other tx",
+			last:   last,
+			msg:    message.DepositMessage{DepositNonce: 10, BlockNumber: 100, SenderTxHash: "0xff"},
+			expect: nonceRepeated,
+		},
+		{
+			name:   "old nonce in new block",
+			last:   last,
+			msg:    message.DepositMessage{DepositNonce: 9, BlockNumber: 120, SenderTxHash: "0x09"},
+			expect: nonceRepeated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, checkDepositNonce(tc.last, tc.msg))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package bridge
+
+import (
+	"berith-swap/bridge/message"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// watchPause는 토큰 컨트랙트의 Paused, Unpaused 이벤트를 구독하여 pause 상태를 갱신합니다.
+// websocket endpoint가 아니거나 구독이 끊긴 동안에는 processQueue가 매번 pause 상태를 조회합니다.
+func (r *ReceiverChain) watchPause() {
+	if !r.c.EvmClient.IsWebsocket() {
+		return
+	}
+	for {
+		err := r.subscribePause()
+		if errors.Is(err, errReceiverStopped) {
+			return
+		}
+		r.c.Logger.Warn().Err(err).Msg("pause event subscription failed. check pause state on every poll")
+
+		select {
+		case <-time.After(QueuePollInterval):
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// subscribePause는 Paused, Unpaused 이벤트를 구독하고 이벤트가 발생할 때마다 pause 상태를 다시 조회합니다.
+func (r *ReceiverChain) subscribePause() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr := *r.erc20Contract.ContractAddress()
+	logCh := make(chan types.Log, 1)
+	pausedSub, err := r.c.EvmClient.SubscribeEventLogs(ctx, addr, message.Paused, logCh)
+	if err != nil {
+		return fmt.Errorf("cannot subscribe paused logs: %w", err)
+	}
+	defer pausedSub.Unsubscribe()
+
+	unpausedSub, err := r.c.EvmClient.SubscribeEventLogs(ctx, addr, message.Unpaused, logCh)
+	if err != nil {
+		return fmt.Errorf("cannot subscribe unpaused logs: %w", err)
+	}
+	defer unpausedSub.Unsubscribe()
+
+	// 구독을 시작하기 전에 변경된 상태는 이벤트로 전달되지 않으므로 직접 조회한다.
+	if _, err := r.refreshPause(); err != nil {
+		return err
+	}
+	r.setWatchingPause(true)
+	defer r.setWatchingPause(false)
+
+	for {
+		select {
+		case <-logCh:
+			// Paused와 Unpaused는 서로 다른 구독으로 전달되어 순서를 보장할 수 없으므로 컨트랙트의 상태를 다시 조회한다.
+			if _, err := r.refreshPause(); err != nil {
+				return err
+			}
+		case err := <-pausedSub.Err():
+			return err
+		case err := <-unpausedSub.Err():
+			return err
+		case <-r.stop:
+			return errReceiverStopped
+		}
+	}
+}
+
+// isPaused는 토큰 컨트랙트가 pause 상태인지 확인합니다.
+// 이벤트를 구독하고 있다면 마지막으로 확인한 상태를, 아니라면 컨트랙트의 상태를 조회하여 반환합니다.
+func (r *ReceiverChain) isPaused() (bool, error) {
+	r.pauseLock.Lock()
+	watching, paused := r.watchingPause, r.paused
+	r.pauseLock.Unlock()
+	if watching {
+		return paused, nil
+	}
+	return r.refreshPause()
+}
+
+// refreshPause는 토큰 컨트랙트의 pause 상태를 조회하여 갱신합니다.
+func (r *ReceiverChain) refreshPause() (bool, error) {
+	paused, err := r.erc20Contract.GetPauseState()
+	if err != nil {
+		return false, fmt.Errorf("cannot get pause state of token. err:%w", err)
+	}
+	r.setPaused(*paused)
+	return *paused, nil
+}
+
+// setPaused는 pause 상태를 갱신하고, pause가 해제되면 queue에 남은 deposit을 바로 처리하도록 알립니다.
+func (r *ReceiverChain) setPaused(paused bool) {
+	r.pauseLock.Lock()
+	changed := r.paused != paused
+	r.paused = paused
+	r.pauseLock.Unlock()
+	if !changed {
+		return
+	}
+
+	if paused {
+		r.c.Logger.Warn().Msg("token is paused. park deposits in queue until unpaused")
+		return
+	}
+	r.c.Logger.Info().Msg("token is unpaused. drain parked deposits")
+	select {
+	case r.resume <- struct{}{}:
+	default:
+	}
+}
+
+func (r *ReceiverChain) setWatchingPause(watching bool) {
+	r.pauseLock.Lock()
+	defer r.pauseLock.Unlock()
+	r.watchingPause = watching
+}
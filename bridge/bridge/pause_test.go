@@ -0,0 +1,32 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPausedSignalsResume(t *testing.T) {
+	r := &ReceiverChain{
+		c:      &chain.Chain{Logger: zerolog.Nop()},
+		resume: make(chan struct{}, 1),
+	}
+
+	r.setPaused(true)
+	require.Len(t, r.resume, 0)
+
+	r.setWatchingPause(true)
+	paused, err := r.isPaused()
+	require.NoError(t, err)
+	require.True(t, paused)
+
+	r.setPaused(false)
+	require.Len(t, r.resume, 1)
+
+	// 상태가 바뀌지 않으면 다시 알리지 않는다.
+	<-r.resume
+	r.setPaused(false)
+	require.Len(t, r.resume, 0)
+}
@@ -0,0 +1,78 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/transaction"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 토큰 지급 방식
+const (
+	PayoutTransfer = "transfer" // owner 계정이 보유한 토큰을 transfer로 지급 (기본값)
+	PayoutMint     = "mint"     // minter 권한을 가진 owner 계정이 토큰을 mint 하여 지급
+)
+
+// payoutStrategy는 deposit의 수신자에게 토큰을 지급하는 방식입니다.
+type payoutStrategy interface {
+	// submit은 to에게 amount 만큼의 토큰을 지급하는 트랜잭션을 전송하고 receipt를 기다리지 않고 반환합니다.
+	submit(to common.Address, amount *big.Int, opts transaction.TransactOptions) (*common.Hash, error)
+	// balance는 owner 계정이 지급할 수 있는 토큰 양을 반환합니다. 제한이 없다면 nil을 반환합니다.
+	balance(owner common.Address) (*big.Int, error)
+}
+
+// transferPayout은 owner 계정이 미리 보유한 토큰을 transfer로 지급합니다.
+type transferPayout struct {
+	token *contract.ERC20Contract
+}
+
+func (p *transferPayout) submit(to common.Address, amount *big.Int, opts transaction.TransactOptions) (*common.Hash, error) {
+	return p.token.SubmitTransfer(to, amount, opts)
+}
+
+func (p *transferPayout) balance(owner common.Address) (*big.Int, error) {
+	return p.token.GetBalance(owner)
+}
+
+// mintPayout은 지급할 때마다 토큰을 mint 하므로 owner 계정이 토큰을 보유할 필요가 없습니다.
+type mintPayout struct {
+	token *contract.MintableTokenContract
+}
+
+func (p *mintPayout) submit(to common.Address, amount *big.Int, opts transaction.TransactOptions) (*common.Hash, error) {
+	return p.token.SubmitMint(to, amount, opts)
+}
+
+func (p *mintPayout) balance(common.Address) (*big.Int, error) {
+	return nil, nil
+}
+
+// setPayoutStrategy는 config의 payoutMode에 따라 토큰 지급 방식을 설정합니다.
+// mint 방식은 owner 계정이 토큰 컨트랙트의 MINTER_ROLE을 가지고 있어야 하며 batch 전송과 함께 사용할 수 없습니다.
+func (r *ReceiverChain) setPayoutStrategy(chainCfg *config.RawChainConfig) {
+	switch chainCfg.PayoutMode {
+	case "", PayoutTransfer:
+		r.strategy = &transferPayout{token: r.erc20Contract}
+	case PayoutMint:
+		if r.batchContract != nil {
+			r.c.Logger.Panic().Msg("batchAddress cannot be used with mint payout mode")
+		}
+		token, err := contract.InitMintableTokenContract(r.c.EvmClient, chainCfg.Erc20Address, &r.c.Logger)
+		if err != nil {
+			r.c.Logger.Panic().Err(err).Msg("cannot init mintable token contract")
+		}
+		minter, err := token.HasMinterRole(r.c.EvmClient.From())
+		if err != nil {
+			r.c.Logger.Panic().Err(err).Msg("cannot check minter role of owner")
+		}
+		if !minter {
+			r.c.Logger.Panic().Msgf("owner doesn't have minter role. owner:%s", r.c.EvmClient.From().Hex())
+		}
+		r.strategy = &mintPayout{token: token}
+		r.c.Logger.Info().Msg("deposits will be paid by minting tokens")
+	default:
+		r.c.Logger.Panic().Msgf("unknown payout mode:%s", chainCfg.PayoutMode)
+	}
+}
@@ -0,0 +1,97 @@
+package bridge
+
+import (
+	"berith-swap/bridge/message"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	DefaultMaxInFlight = 1
+
+	errReceiverStopped = errors.New("receiver chain stopped")
+)
+
+// submitInFlight는 전송 가능한 자리가 생길 때까지 기다린 뒤 토큰 전송 트랜잭션을 전송하고,
+// receipt 확인과 swap history 저장은 별도의 goroutine에서 처리합니다.
+// 전송에 실패한 deposit은 재시도 대상으로 기록합니다.
+func (r *ReceiverChain) submitInFlight(m message.DepositMessage) error {
+	select {
+	case r.slots <- struct{}{}:
+	case <-r.stop:
+		return errReceiverStopped
+	}
+
+	// 서명된 트랜잭션이 submitted 상태로 저장되기 전에 처리 중으로 표시하여 reconcile 대상에서 제외한다.
+	r.markInFlight(m)
+	txHash, err := r.submitTransfer(m)
+	if err != nil {
+		r.unmarkInFlight(m)
+		<-r.slots
+		return r.handleSubmitError(m, err)
+	}
+
+	go r.trackInFlight(m, txHash)
+	return nil
+}
+
+// trackInFlight는 전송된 트랜잭션의 receipt를 기다리고 결과를 저장합니다.
+// 트랜잭션이 revert 되었다면 재시도 대상으로 기록하고, 결과를 저장하지 못하면 confirmErr로 에러를 전달합니다.
+func (r *ReceiverChain) trackInFlight(m message.DepositMessage, txHash *common.Hash) {
+	err := r.handleConfirmError(m, r.confirmTransfer(m, txHash))
+
+	r.unmarkInFlight(m)
+	<-r.slots
+
+	if err != nil {
+		r.confirmErr <- err
+	}
+}
+
+// markInFlight는 deposit을 처리 중으로 표시하여 다음 queue 조회에서 제외되도록 합니다.
+func (r *ReceiverChain) markInFlight(msgs ...message.DepositMessage) {
+	r.inFlightLock.Lock()
+	defer r.inFlightLock.Unlock()
+	for _, m := range msgs {
+		r.inFlight[m.SenderTxHash] = r.quote(m).amount
+	}
+}
+
+// unmarkInFlight는 deposit의 처리 중 표시를 해제합니다.
+func (r *ReceiverChain) unmarkInFlight(msgs ...message.DepositMessage) {
+	r.inFlightLock.Lock()
+	defer r.inFlightLock.Unlock()
+	for _, m := range msgs {
+		delete(r.inFlight, m.SenderTxHash)
+	}
+}
+
+// inFlightCount는 처리 중인 deposit 수를 반환합니다.
+func (r *ReceiverChain) inFlightCount() int {
+	r.inFlightLock.Lock()
+	defer r.inFlightLock.Unlock()
+	return len(r.inFlight)
+}
+
+// reservedAmount는 처리 중인 deposit의 지급액 합계를 반환합니다.
+func (r *ReceiverChain) reservedAmount() *big.Int {
+	r.inFlightLock.Lock()
+	defer r.inFlightLock.Unlock()
+	total := new(big.Int)
+	for _, amount := range r.inFlight {
+		if amount != nil {
+			total.Add(total, amount)
+		}
+	}
+	return total
+}
+
+// isInFlight는 deposit의 토큰 전송 트랜잭션이 receipt를 기다리고 있는지 확인합니다.
+func (r *ReceiverChain) isInFlight(senderTxHash string) bool {
+	r.inFlightLock.Lock()
+	defer r.inFlightLock.Unlock()
+	_, ok := r.inFlight[senderTxHash]
+	return ok
+}
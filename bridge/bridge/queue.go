@@ -0,0 +1,83 @@
+package bridge
+
+import (
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	QueuePollInterval = time.Second * 3
+	QueueBatchSize    = int32(MsgChanSize)
+)
+
+// enqueueDeposits는 감지된 deposit을 deposit queue에 저장합니다.
+// 이미 저장된 deposit은 무시되므로 같은 블록을 다시 탐색해도 안전합니다.
+func enqueueDeposits(ctx context.Context, st *store.Store, msgs []message.DepositMessage) error {
+	for _, m := range msgs {
+		_, err := st.EnqueueDeposit(ctx, newEnqueueDepositParams(m))
+		if err != nil {
+			return fmt.Errorf("cannot enqueue deposit. hash:%s, err:%w", m.SenderTxHash, err)
+		}
+	}
+	return nil
+}
+
+// heldDeposit은 자동으로 지급하지 않고 held 상태로 저장할 deposit과 그 사유입니다.
+type heldDeposit struct {
+	msg    message.DepositMessage
+	reason string
+}
+
+// enqueueHeldDeposits는 held로 분류된 deposit을 deposit queue에 held 상태로 저장하고 검토 대상으로 기록합니다.
+// 이미 저장된 deposit의 상태는 변경하지 않습니다.
+func enqueueHeldDeposits(ctx context.Context, st *store.Store, held []heldDeposit) error {
+	for _, h := range held {
+		err := st.EnqueueHeldDepositTx(ctx, newEnqueueDepositParams(h.msg), h.reason)
+		if err != nil {
+			return fmt.Errorf("cannot enqueue held deposit. hash:%s, err:%w", h.msg.SenderTxHash, err)
+		}
+	}
+	return nil
+}
+
+// newEnqueueDepositParams는 DepositMessage를 deposit queue row로 변환합니다.
+func newEnqueueDepositParams(m message.DepositMessage) mariadb.EnqueueDepositParams {
+	amount := "0"
+	if m.Amount != nil {
+		amount = m.Amount.String()
+	}
+	return mariadb.EnqueueDepositParams{
+		SenderTxHash:    m.SenderTxHash,
+		BlockNumber:     int64(m.BlockNumber),
+		BlockHash:       m.BlockHash.Hex(),
+		DepositNonce:    int64(m.DepositNonce),
+		SenderAddress:   m.Sender.Hex(),
+		ReceiverAddress: m.Receiver.Hex(),
+		Amount:          amount,
+		Direction:       store.DirectionForward,
+	}
+}
+
+// depositFromQueue는 deposit queue row를 DepositMessage로 변환합니다.
+func depositFromQueue(row mariadb.BersDepositQueue) (message.DepositMessage, error) {
+	amount, ok := new(big.Int).SetString(row.Amount, 10)
+	if !ok {
+		return message.DepositMessage{}, fmt.Errorf("invalid deposit amount. hash:%s, amount:%s", row.SenderTxHash, row.Amount)
+	}
+	return message.NewDepositMessage(
+		uint64(row.BlockNumber),
+		common.HexToHash(row.BlockHash),
+		uint64(row.DepositNonce),
+		common.HexToAddress(row.SenderAddress),
+		common.HexToAddress(row.ReceiverAddress),
+		amount,
+		row.SenderTxHash,
+	), nil
+}
@@ -0,0 +1,42 @@
+package bridge
+
+import (
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store/mariadb"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositQueueConversion(t *testing.T) {
+	amount, ok := new(big.Int).SetString("123456789000000000000000000", 10)
+	require.True(t, ok)
+
+	m := message.NewDepositMessage(
+		100,
+		common.HexToHash("0x01"),
+		7,
+		common.HexToAddress("0x02"),
+		common.HexToAddress("0x03"),
+		amount,
+		common.HexToHash("0x04").Hex(),
+	)
+
+	params := newEnqueueDepositParams(m)
+	got, err := depositFromQueue(mariadb.BersDepositQueue{
+		SenderTxHash:    params.SenderTxHash,
+		BlockNumber:     params.BlockNumber,
+		BlockHash:       params.BlockHash,
+		DepositNonce:    params.DepositNonce,
+		SenderAddress:   params.SenderAddress,
+		ReceiverAddress: params.ReceiverAddress,
+		Amount:          params.Amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, m, got)
+
+	_, err = depositFromQueue(mariadb.BersDepositQueue{Amount: "invalid"})
+	require.Error(t, err)
+}
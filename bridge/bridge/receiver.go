@@ -1,7 +1,6 @@
 package bridge
 
 import (
-	"berith-swap/bridge/blockstore"
 	"berith-swap/bridge/chain"
 	"berith-swap/bridge/config"
 	"berith-swap/bridge/contract"
@@ -15,22 +14,56 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// ReceiverChain은 SenderChain 측에서 전송된 코인 예치 메시지를 수신하고 토큰 컨트랙트를 통해 해당 사용자에게 토큰을 전송합니다.
+// ReceiverChain은 deposit queue에 저장된 코인 예치 내역을 조회하고 토큰 컨트랙트를 통해 해당 사용자에게 토큰을 전송합니다.
 type ReceiverChain struct {
 	c             *chain.Chain
-	msgChan       <-chan message.DepositMessage
 	erc20Contract *contract.ERC20Contract
-	blockStore    *blockstore.Blockstore
 	stop          chan struct{}
 	store         *store.Store
+	slots         chan struct{}       // 동시에 전송 중인 트랜잭션 수를 제한하기 위한 semaphore
+	inFlight      map[string]*big.Int // 전송을 기다리거나 receipt를 기다리고 있는 deposit의 sender tx hash와 지급액
+	inFlightLock  sync.Mutex
+	confirmErr    chan error
+	batchContract *contract.BatchTransferContract
+	batchSize     int
+	batchWindow   time.Duration
+	batch         []message.DepositMessage // 한 번의 트랜잭션으로 전송하기 위해 모으고 있는 deposit
+	batchStarted  time.Time
+	fallback      []message.DepositMessage // revert 된 batch에서 개별 트랜잭션으로 지급할 deposit
+	fallbackLock  sync.Mutex
+	retry         retryPolicy
+	conversion    *conversion
+	fee           *feeModel
+	treasury      *common.Address // 수수료를 sweep 할 주소
+	limits        *swapLimits
+	screener      *screener
+	strategy      payoutStrategy
+	reverse       *reverseWatcher // 토큰 반환을 감지하지 않는다면 nil
+
+	approvalThreshold *big.Int // 운영자 승인이 필요한 예치액의 기준
+
+	lowTokenBalance *big.Int // 경고를 남길 토큰 잔액의 기준
+	lowGasBalance   *big.Int // 경고를 남길 native coin 잔액의 기준
+	lowToken        bool
+	lowGas          bool
+	liquidityShort  bool // 잔액 부족으로 지급이 멈춘 상태
+
+	pauseLock     sync.Mutex
+	paused        bool          // 토큰 컨트랙트의 pause 상태
+	watchingPause bool          // Paused, Unpaused 이벤트를 구독하고 있는지 여부
+	resume        chan struct{} // pause가 해제되었음을 알리는 channel
 }
 
 // NewReceiverChain는 ReceiverChain을 생성합니다.
-func NewReceiverChain(ch <-chan message.DepositMessage, cfg *config.Config, idx int, bs *blockstore.Blockstore) *ReceiverChain {
+func NewReceiverChain(cfg *config.Config, idx int) *ReceiverChain {
 	chainCfg := cfg.ChainConfig[idx]
 
 	chain, err := chain.NewChain(cfg, idx)
@@ -48,16 +81,47 @@ func NewReceiverChain(ch <-chan message.DepositMessage, cfg *config.Config, idx
 		chain.Logger.Panic().Err(err).Msg("cannot init remote db store")
 	}
 
+	maxInFlight, err := strconv.Atoi(chainCfg.MaxInFlight)
+	if err != nil || maxInFlight <= 0 {
+		chain.Logger.Info().Msgf("max in-flight transfers is not set. set default:%d", DefaultMaxInFlight)
+		maxInFlight = DefaultMaxInFlight
+	}
+
 	rc := ReceiverChain{
 		c:             chain,
-		blockStore:    bs,
-		msgChan:       ch,
 		erc20Contract: newErc20,
 		stop:          make(chan struct{}),
 		store:         store,
+		slots:         make(chan struct{}, maxInFlight),
+		inFlight:      make(map[string]*big.Int),
+		confirmErr:    make(chan error, maxInFlight),
+		resume:        make(chan struct{}, 1),
 	}
 	rc.setReceiverErc20Contract(chainCfg)
-	go rc.listen()
+	rc.setReverseWatcher(cfg, chainCfg)
+	rc.setBatchTransferContract(chainCfg)
+	rc.setPayoutStrategy(chainCfg)
+	rc.retry = newRetryPolicy(chainCfg, &rc.c.Logger)
+	rc.setLowBalanceThresholds(chainCfg)
+	rc.setApprovalThreshold(chainCfg)
+
+	rc.conversion, err = newConversion(chainCfg)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("invalid conversion config")
+	}
+	rc.fee, err = newFeeModel(chainCfg)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("invalid fee config")
+	}
+	rc.setTreasury(chainCfg)
+	rc.limits, err = newSwapLimits(chainCfg)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("invalid swap limit config")
+	}
+	rc.screener, err = newScreener(chainCfg)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("invalid address screening config")
+	}
 	return &rc
 }
 
@@ -85,74 +149,354 @@ func (s *ReceiverChain) setReceiverErc20Contract(chainCfg *config.RawChainConfig
 
 // start는 ReceiverChain을 시작합니다.
 func (r *ReceiverChain) start(ch chan error) {
+	go r.watchPause()
+	if r.reverse != nil {
+		go r.watchReverse()
+	}
 	ch <- r.listen()
 }
 
-// listen은 ReceiverChain의 메시지 채널을 수신하고 토큰을 전송합니다.
+// listen은 submitted 상태로 남은 deposit을 먼저 정리한 뒤 주기적으로 deposit queue를 조회하고 토큰을 전송합니다.
+// 지급에 실패한 deposit은 재시도 대상으로 기록되며 다른 deposit의 지급은 계속됩니다.
 func (r *ReceiverChain) listen() error {
+	err := r.reconcile(true)
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msg("cannot reconcile submitted deposits. stop receiver chain.")
+		return err
+	}
+
+	ticker := time.NewTicker(QueuePollInterval)
+	defer ticker.Stop()
+	reconcileTicker := time.NewTicker(ReconcileInterval)
+	defer reconcileTicker.Stop()
 	for {
+		err := r.processQueue()
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msg("error occured during send token. stop receiver chain.")
+			return err
+		}
+
 		select {
-		case m := <-r.msgChan:
-			valErr := util.ValidateStruct(m)
-			if valErr != nil {
-				r.c.Logger.Warn().Msgf("Invalid deposit message. %s", valErr.Error())
-				continue
-			}
-			err := r.SendToken(m)
+		case <-ticker.C:
+		case <-r.resume:
+		case <-reconcileTicker.C:
+			err := r.reconcile(false)
 			if err != nil {
-				r.c.Logger.Error().Err(err).Msg("error occured during send token. stop receiver chain.")
+				r.c.Logger.Error().Err(err).Msg("cannot reconcile submitted deposits. stop receiver chain.")
 				return err
 			}
+		case err := <-r.confirmErr:
+			r.c.Logger.Error().Err(err).Msg("error occured during confirm token transfer. stop receiver chain.")
+			return err
 		case <-r.stop:
 			r.c.Logger.Error().Msg("receiver chain got stop sign")
-			return errors.New("receiver chain stopped")
+			return errReceiverStopped
 		}
+	}
+}
 
+// processQueue는 아직 지급되지 않았고 재시도 대기 중이 아닌 deposit을 감지된 순서대로 전송합니다.
+// 전송한 트랜잭션의 receipt는 기다리지 않고, 최대 maxInFlight 개의 트랜잭션을 동시에 전송합니다.
+// batch 전송이 설정되어 있다면 deposit을 모아 batchWindow가 지나거나 batchSize가 차면 한 번에 전송합니다.
+// 토큰이 pause 상태이거나 가스비 잔액이 부족하다면 남은 deposit은 queue에 남겨두고 다음 조회에서 다시 확인합니다.
+// 토큰 잔액이 부족한 deposit은 queue에 남겨두고, 남은 잔액으로 지급할 수 있는 다음 deposit을 처리합니다.
+// 주소 screening에 걸린 deposit은 운영자의 승인 여부와 관계없이 held 상태로 보류하고,
+// 운영자가 승인하지 않은 deposit 중 지급 한도를 넘는 deposit은 held 상태로, 승인 기준 이상인 deposit은 pending_approval 상태로 보류합니다.
+// 수수료가 환산된 토큰 양 이상인 deposit도 held 상태로 보류하여 운영자가 거절하면 환불할 수 있도록 합니다.
+func (r *ReceiverChain) processQueue() error {
+	r.reloadScreening()
+
+	paused, err := r.isPaused()
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msg("cannot check pause state. retry next poll")
+		return nil
+	}
+	if paused {
+		// pause가 해제될 때까지 deposit은 detected 상태로 queue에 남겨두고, 해제되면 감지된 순서대로 처리한다.
+		return nil
 	}
+
+	err = r.payFallback()
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.store.ListPendingDeposits(context.Background(), mariadb.ListPendingDepositsParams{
+		Direction: store.DirectionForward,
+		Limit:     QueueBatchSize + int32(r.inFlightCount()),
+	})
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msg("cannot get deposits from deposit queue")
+		return err
+	}
+
+	var (
+		liq   *liquidity
+		usage *limitUsage
+		short error
+	)
+	for _, row := range rows {
+		if r.isInFlight(row.SenderTxHash) {
+			continue
+		}
+
+		m, err := depositFromQueue(row)
+		if err != nil {
+			r.c.Logger.Warn().Err(err).Msg("Invalid deposit message.")
+			if err := r.updateDepositStatus(row.SenderTxHash, store.DepositInvalid); err != nil {
+				return err
+			}
+			continue
+		}
+		if valErr := util.ValidateStruct(m); valErr != nil {
+			r.c.Logger.Warn().Msgf("Invalid deposit message. %s", valErr.Error())
+			if err := r.updateDepositStatus(row.SenderTxHash, store.DepositInvalid); err != nil {
+				return err
+			}
+			continue
+		}
+
+		swapped, err := r.isSwapped(m.SenderTxHash)
+		if err != nil {
+			return err
+		}
+		if swapped {
+			r.c.Logger.Warn().Msgf("swap already excecuted, ignore deposit message. sender tx: %s", m.SenderTxHash)
+			if err := r.updateDepositStatus(m.SenderTxHash, store.DepositConfirmed); err != nil {
+				return err
+			}
+			continue
+		}
+
+		q := r.quote(m)
+		parked, err := r.holdUncoveredFee(m, q)
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msg("cannot hold deposit not covering the fee. retry next poll")
+			break
+		}
+		if parked {
+			continue
+		}
+
+		parked, err = r.screenDeposit(m)
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msg("cannot hold screened deposit. retry next poll")
+			break
+		}
+		if parked {
+			continue
+		}
+
+		if usage == nil {
+			usage, err = r.loadLimitUsage()
+			if err != nil {
+				r.c.Logger.Error().Err(err).Msg("cannot check swap limits. retry next poll")
+				break
+			}
+		}
+		if !row.ApprovedAt.Valid {
+			parked, err := r.requireReview(usage, m)
+			if err != nil {
+				r.c.Logger.Error().Err(err).Msg("cannot check swap limits. retry next poll")
+				break
+			}
+			if parked {
+				continue
+			}
+		}
+
+		if liq == nil {
+			liq, err = r.loadLiquidity()
+			if err != nil {
+				r.c.Logger.Error().Err(err).Msg("cannot check receiver balance. retry next poll")
+				break
+			}
+		}
+		txs := 1
+		if r.batchContract != nil && len(r.batch)%r.batchSize != 0 {
+			txs = 0
+		}
+		err = liq.take(q.amount, txs)
+		if errors.Is(err, errInsufficientToken) {
+			// 잔액이 채워질 때까지 deposit은 detected 상태로 queue에 남겨두고, 지급액이 더 적은 다음 deposit을 확인한다.
+			short = err
+			continue
+		}
+		if err != nil {
+			// 가스비가 채워질 때까지 남은 deposit은 detected 상태로 queue에 남겨둔다.
+			short = err
+			break
+		}
+
+		if r.batchContract != nil {
+			r.addToBatch(m)
+			continue
+		}
+		err = r.submitInFlight(m)
+		if err != nil {
+			return err
+		}
+	}
+	if liq != nil {
+		r.setLiquidityShort(short)
+	}
+	return r.flushBatch()
 }
 
 // SendToken은 Deposit 메시지를 수신하고 토큰을 전송합니다.
 func (r *ReceiverChain) SendToken(m message.DepositMessage) error {
-	history, err := r.store.GetBersSwapHistory(context.Background(), m.SenderTxHash)
+	swapped, err := r.isSwapped(m.SenderTxHash)
+	if err != nil {
+		return err
+	}
+	if swapped {
+		r.c.Logger.Warn().Msgf("swap already excecuted, ignore deposit message. sender tx: %s", m.SenderTxHash)
+		return r.updateDepositStatus(m.SenderTxHash, store.DepositConfirmed)
+	}
+
+	return r.payout(m, false)
+}
+
+// updateDepositStatus는 deposit queue에 저장된 deposit의 상태를 변경합니다.
+func (r *ReceiverChain) updateDepositStatus(senderTxHash, status string) error {
+	err := r.store.UpdateDepositStatus(context.Background(), mariadb.UpdateDepositStatusParams{
+		Status:       status,
+		SenderTxHash: senderTxHash,
+	})
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msgf("cannot update deposit status. hash:%s, status:%s", senderTxHash, status)
+	}
+	return err
+}
+
+// isSwapped는 sender tx hash에 해당하는 swap history가 이미 존재하는지 확인합니다.
+func (r *ReceiverChain) isSwapped(senderTxHash string) (bool, error) {
+	history, err := r.store.GetBersSwapHistory(context.Background(), senderTxHash)
 	if err != nil {
 		if err != sql.ErrNoRows {
-			r.c.Logger.Error().Err(err).Msgf("cannot get swab history from remote store. hash:%s", m.SenderTxHash)
+			r.c.Logger.Error().Err(err).Msgf("cannot get swab history from remote store. hash:%s", senderTxHash)
+			return false, err
+		}
+	}
+	return history.SenderTxHash != "", nil
+}
+
+// payout은 지급 한도와 잔액을 확인한 뒤 Deposit 메시지의 수신자에게 토큰을 전송하고 swap history를 저장합니다.
+// 수수료가 환산된 토큰 양 이상이거나 주소 screening에 걸린 deposit은 항상 보류하며, approved가 false라면 승인 기준 이상이거나 지급 한도를 넘는 deposit도 보류합니다.
+func (r *ReceiverChain) payout(m message.DepositMessage, approved bool) error {
+	q := r.quote(m)
+	parked, err := r.holdUncoveredFee(m, q)
+	if err != nil || parked {
+		return err
+	}
+	parked, err = r.screenDeposit(m)
+	if err != nil || parked {
+		return err
+	}
+	if !approved {
+		usage, err := r.loadLimitUsage()
+		if err != nil {
+			return err
+		}
+		parked, err := r.requireReview(usage, m)
+		if err != nil || parked {
 			return err
 		}
 	}
 
-	if history.SenderTxHash != "" {
-		r.c.Logger.Warn().Msgf("swap already excecuted, ignore deposit message. sender tx: %s", history.SenderTxHash)
-		return nil
+	liq, err := r.loadLiquidity()
+	if err != nil {
+		return err
+	}
+	err = liq.take(q.amount, 1)
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msgf("cannot pay out deposit. hash:%s", m.SenderTxHash)
+		return err
 	}
 
-	txHash, err := r.erc20Contract.Transfer(m.Receiver, m.Amount, transaction.TransactOptions{GasLimit: r.c.GasLimit.Uint64()})
+	txHash, err := r.submitTransfer(m)
 	if err != nil {
-		r.c.Logger.Error().Err(err).Any("Address", m.Receiver.Hex()).Any("Value", m.Amount.Uint64()).Msg("transaction submit failed.")
 		return err
 	}
+	return r.confirmTransfer(m, txHash)
+}
+
+// submitTransfer는 설정된 지급 방식으로 Deposit 메시지의 수신자에게 토큰을 지급하는 트랜잭션을 전송합니다.
+// 트랜잭션을 전송하기 전에 서명된 tx hash와 nonce를 deposit queue에 submitted 상태로 저장합니다.
+func (r *ReceiverChain) submitTransfer(m message.DepositMessage) (*common.Hash, error) {
+	q := r.quote(m)
+	opts := transaction.TransactOptions{
+		GasLimit: r.c.GasLimit.Uint64(),
+		OnSigned: func(hash common.Hash, nonce uint64) error {
+			return r.markSubmitted(hash, nonce, []message.DepositMessage{m}, []quote{q})
+		},
+	}
+	amount := q.amount
+	txHash, err := r.strategy.submit(m.Receiver, amount, opts)
+	if err != nil {
+		r.c.Logger.Error().Err(err).Any("Address", m.Receiver.Hex()).Any("Value", amount.String()).Msg("transaction submit failed.")
+		return nil, err
+	}
+	r.c.Logger.Info().Msgf("submitted token transfer. sender tx: %s, Tx Hash: %s", m.SenderTxHash, txHash.Hex())
+	return txHash, nil
+}
 
+// confirmTransfer는 토큰 전송 트랜잭션의 receipt를 기다린 뒤 swap history를 저장합니다.
+// 트랜잭션이 revert 되었다면 errTransferReverted를, receipt를 확인하지 못했다면 errTransferUnconfirmed를 반환합니다.
+func (r *ReceiverChain) confirmTransfer(m message.DepositMessage, txHash *common.Hash) error {
 	rec, err := r.erc20Contract.WaitAndReturnTxReceipt(txHash)
 	if err != nil {
 		r.c.Logger.Error().Err(err).Msgf("cannot get tx receipt hash:%s", txHash.Hex())
-		return err
+		if rec != nil && rec.Status == types.ReceiptStatusFailed {
+			return fmt.Errorf("%w. tx:%s", errTransferReverted, txHash.Hex())
+		}
+		return fmt.Errorf("%w. tx:%s, err:%v", errTransferUnconfirmed, txHash.Hex(), err)
 	}
 
 	gasUsed := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).SetUint64(rec.GasUsed)), new(big.Float).SetInt(big.NewInt(1e18)))
 	r.c.Logger.Info().Msgf("receive tx receipt successfully. Block: %s, Tx Hash: %s, GasUsed: %s", rec.BlockNumber, txHash.Hex(), gasUsed.String())
 
-	err = r.blockStore.StoreBlock(new(big.Int).SetUint64(m.BlockNumber))
+	return r.recordPayout(m, txHash)
+}
+
+// markSubmitted는 deposit을 submitted 상태로 변경하고 토큰 전송 트랜잭션의 hash, nonce와 트랜잭션으로 지급하는 quotes를 저장합니다.
+// detected 상태가 아닌 deposit이 있다면 에러를 반환하여 트랜잭션이 전송되지 않도록 합니다.
+func (r *ReceiverChain) markSubmitted(hash common.Hash, nonce uint64, msgs []message.DepositMessage, quotes []quote) error {
+	args := make([]mariadb.MarkDepositSubmittedParams, len(msgs))
+	for i, m := range msgs {
+		args[i] = quotes[i].submittedParams(m.SenderTxHash, hash, nonce)
+	}
+	err := r.store.MarkDepositsSubmittedTx(context.Background(), args...)
 	if err != nil {
-		r.c.Logger.Error().Err(err).Msg("Failed to write latest block to blockstore")
+		r.c.Logger.Error().Err(err).Msgf("cannot mark deposit as submitted. deposits:%d, tx:%s", len(msgs), hash.Hex())
 		return err
 	}
-	r.c.Logger.Info().Msgf("saved the block number where the deposit event occurred. number: %d", m.BlockNumber)
+	return nil
+}
 
-	err = r.store.CreateSwapHistoryTx(context.Background(), mariadb.CreateBersSwapHistoryParams{
+// recordPayout은 토큰 전송이 완료된 deposit의 swap history를 예치액, 환산액, 수수료, 지급액과 함께 저장합니다.
+// 환율이나 수수료 설정이 바뀌었을 수 있으므로 트랜잭션을 전송할 때 queue에 저장한 지급액을 기록합니다.
+func (r *ReceiverChain) recordPayout(m message.DepositMessage, txHash *common.Hash) error {
+	row, err := r.store.GetDeposit(context.Background(), m.SenderTxHash)
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msgf("cannot get deposit from deposit queue. hash:%s", m.SenderTxHash)
+		return err
+	}
+	q, ok := submittedQuote(row)
+	if !ok {
+		r.c.Logger.Warn().Msgf("submitted payout amount is not stored. record current quote. hash:%s", m.SenderTxHash)
+		q = r.quote(m)
+	}
+	err = r.store.CompleteDepositTx(context.Background(), mariadb.CreateBersSwapHistoryParams{
 		SenderTxHash:   m.SenderTxHash,
 		ReceiverTxHash: txHash.Hex(),
 		BerithAddress:  m.Sender.Hex(),
-		Amount:         m.Amount.Int64(),
+		Amount:         legacyAmount(q.deposit),
+		DepositAmount:  q.deposit.String(),
+		GrossAmount:    q.gross.String(),
+		FeeAmount:      q.fee.String(),
+		PayoutAmount:   q.amount.String(),
+		Direction:      store.DirectionForward,
 	})
 	if err != nil {
 		r.c.Logger.Error().Err(err).Msg("Failed to store swap history to remote db")
@@ -165,6 +509,6 @@ func (r *ReceiverChain) SendToken(m message.DepositMessage) error {
 
 // Stop는 ReceiverChain을 종료합니다.
 func (r *ReceiverChain) Stop() {
+	close(r.stop)
 	r.store.Stop()
-	r.stop <- struct{}{}
 }
@@ -1,7 +1,6 @@
 package bridge
 
 import (
-	"berith-swap/bridge/blockstore"
 	"berith-swap/bridge/keypair"
 	"berith-swap/bridge/message"
 	"berith-swap/bridge/store/mariadb"
@@ -21,34 +20,28 @@ func TestStoreHistory(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
-	ch := make(chan message.DepositMessage)
-
 	cfg := initTestconfig(t)
 
-	chainCfg := cfg.ChainConfig[ReceiverIdx]
-
-	bs, err := blockstore.NewBlockstore("../../run_test/blockstore", chainCfg.Name)
-	require.NoError(t, err)
-	require.NotNil(t, bs)
-
-	rc := NewReceiverChain(ch, cfg, ReceiverIdx, bs)
+	rc := NewReceiverChain(cfg, ReceiverIdx)
 	defer rc.Stop()
+	go rc.start(make(chan error, 1))
 
 	txBytes := make([]byte, common.HashLength)
-	_, err = rand.Read(txBytes)
+	_, err := rand.Read(txBytes)
 	require.NoError(t, err)
 
 	senderTx := hexutil.Encode(txBytes)
 	receiver, err := keypair.GenerateKeyPair(testAccount, testKeyDir, testPW)
 	require.NoError(t, err)
 
-	ch <- message.DepositMessage{
+	err = enqueueDeposits(context.Background(), rc.store, []message.DepositMessage{{
 		BlockNumber:  big.NewInt(1).Uint64(),
 		Amount:       big.NewInt(1),
 		Sender:       receiver.CommonAddress(),
 		Receiver:     receiver.CommonAddress(),
 		SenderTxHash: senderTx,
-	}
+	}})
+	require.NoError(t, err)
 
 	var hist mariadb.BersSwapHist
 	for i := 0; i < 10; i++ {
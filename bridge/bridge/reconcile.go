@@ -0,0 +1,204 @@
+package bridge
+
+import (
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ReconcileBatchSize = int32(1000)
+	ReconcileInterval  = time.Minute
+	// DroppedTransferTimeout이 지나도록 체인과 mempool에서 찾을 수 없는 토큰 전송 트랜잭션은 mempool에서 사라진 것으로 판단합니다.
+	DroppedTransferTimeout = time.Minute * 30
+)
+
+// ReasonTransferNotFound는 계정의 nonce가 지났는데도 토큰 전송 트랜잭션을 찾을 수 없어 지급 여부를 확인할 수 없는 deposit의 보류 사유입니다.
+const ReasonTransferNotFound = "submitted transfer not found after its nonce was used"
+
+// submittedTransfer는 같은 토큰 전송 트랜잭션으로 지급된 deposit의 묶음입니다.
+// batch 전송이라면 여러 deposit이 하나의 트랜잭션을 공유합니다.
+type submittedTransfer struct {
+	hash        common.Hash
+	nonce       uint64
+	submittedAt time.Time // deposit이 submitted 상태로 변경된 가장 늦은 시각
+	msgs        []message.DepositMessage
+}
+
+// groupSubmitted는 submitted 상태의 deposit을 토큰 전송 트랜잭션 별로 묶어 nonce 순서로 반환합니다.
+func groupSubmitted(rows []mariadb.BersDepositQueue) ([]*submittedTransfer, error) {
+	var transfers []*submittedTransfer
+	byHash := make(map[string]*submittedTransfer)
+	for _, row := range rows {
+		if !row.ReceiverTxHash.Valid || !row.ReceiverNonce.Valid {
+			return nil, fmt.Errorf("submitted deposit has no receiver transaction. hash:%s", row.SenderTxHash)
+		}
+		m, err := depositFromQueue(row)
+		if err != nil {
+			return nil, err
+		}
+
+		t, ok := byHash[row.ReceiverTxHash.String]
+		if !ok {
+			t = &submittedTransfer{
+				hash:  common.HexToHash(row.ReceiverTxHash.String),
+				nonce: uint64(row.ReceiverNonce.Int64),
+			}
+			byHash[row.ReceiverTxHash.String] = t
+			transfers = append(transfers, t)
+		}
+		t.msgs = append(t.msgs, m)
+		if row.UpdatedAt.Valid && row.UpdatedAt.Time.After(t.submittedAt) {
+			t.submittedAt = row.UpdatedAt.Time
+		}
+	}
+
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return transfers[i].nonce < transfers[j].nonce
+	})
+	return transfers, nil
+}
+
+// reconcile은 submitted 상태로 남은 deposit을 체인의 상태와 비교하여 정리합니다.
+// 토큰 전송 트랜잭션이 성공했다면 swap history를 저장하고, revert 되었다면 재시도 대상으로 기록하며,
+// 트랜잭션이 체인에 존재하지 않는다면 다시 지급할 수 있도록 detected 상태로 되돌리고,
+// 계정의 nonce가 이미 트랜잭션의 nonce를 지났다면 held 상태로 보류합니다.
+// startup이 false라면 처리 중인 deposit은 제외합니다.
+func (r *ReceiverChain) reconcile(startup bool) error {
+	rows, err := r.store.ListDepositsByStatus(context.Background(), mariadb.ListDepositsByStatusParams{
+		Direction: store.DirectionForward,
+		Status:    store.DepositSubmitted,
+		Limit:     ReconcileBatchSize,
+	})
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msg("cannot get submitted deposits from deposit queue")
+		return err
+	}
+
+	pending := rows[:0]
+	for _, row := range rows {
+		if !r.isInFlight(row.SenderTxHash) {
+			pending = append(pending, row)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	transfers, err := groupSubmitted(pending)
+	if err != nil {
+		return err
+	}
+
+	r.c.Logger.Info().Msgf("reconcile submitted deposits. deposits:%d, transactions:%d", len(pending), len(transfers))
+	for _, t := range transfers {
+		err := r.reconcileTransfer(t, startup)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileTransfer는 토큰 전송 트랜잭션 하나의 결과를 확인하고 deposit의 상태를 변경합니다.
+// 트랜잭션이 아직 pending 상태라면 다음 reconcile까지 기다립니다.
+func (r *ReceiverChain) reconcileTransfer(t *submittedTransfer, startup bool) error {
+	rec, err := r.c.EvmClient.TransactionReceipt(context.Background(), t.hash)
+	if err == nil {
+		return r.resolveTransfer(t, rec)
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("cannot get receipt of submitted transfer. tx:%s, err:%w", t.hash.Hex(), err)
+	}
+
+	_, _, err = r.c.EvmClient.GetTransactionByHash(t.hash)
+	if err == nil {
+		r.c.Logger.Info().Msgf("submitted transfer is pending. tx:%s", t.hash.Hex())
+		return nil
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("cannot get submitted transfer. tx:%s, err:%w", t.hash.Hex(), err)
+	}
+
+	// 서명 후 전송되지 않았거나 mempool에서 사라진 트랜잭션이다.
+	// 계정의 nonce가 이미 해당 nonce를 지났다면 노드가 트랜잭션을 찾지 못할 뿐 지급되었을 수 있으므로
+	// 다시 지급하지 않고 운영자가 확인할 때까지 held 상태로 보류한다.
+	// 시작 시점이라면 다음 토큰 전송 트랜잭션이 해당 nonce를 사용하게 되므로 다시 지급해도 안전하다.
+	// DroppedTransferTimeout이 지나도록 찾을 수 없다면 mempool에서 사라진 것으로 보고,
+	// 다음 토큰 전송 트랜잭션이 비어있는 nonce를 다시 사용하도록 계정의 nonce를 다시 조회한다.
+	nonce, err := r.c.EvmClient.NonceAt(context.Background(), r.c.EvmClient.From(), nil)
+	if err != nil {
+		return fmt.Errorf("cannot get account nonce. err:%w", err)
+	}
+	switch {
+	case t.nonce < nonce:
+		return r.holdTransfer(t, fmt.Sprintf("%s. tx:%s, nonce:%d, account nonce:%d", ReasonTransferNotFound, t.hash.Hex(), t.nonce, nonce))
+	case startup:
+		r.c.Logger.Warn().Msgf("submitted transfer was not broadcast. tx:%s, nonce:%d", t.hash.Hex(), t.nonce)
+	case time.Since(t.submittedAt) >= DroppedTransferTimeout:
+		r.c.Logger.Warn().Msgf("submitted transfer was dropped from mempool. pay again. tx:%s, nonce:%d, submitted at:%s", t.hash.Hex(), t.nonce, t.submittedAt)
+		r.c.EvmClient.ResetNonce()
+	default:
+		r.c.Logger.Warn().Msgf("submitted transfer not found. tx:%s, nonce:%d", t.hash.Hex(), t.nonce)
+		return nil
+	}
+	return r.resetDeposits(t.msgs...)
+}
+
+// holdTransfer는 지급 여부를 확인할 수 없는 토큰 전송 트랜잭션의 deposit을 수동 검토 대상으로 기록하고 held 상태로 보류합니다.
+func (r *ReceiverChain) holdTransfer(t *submittedTransfer, reason string) error {
+	for _, m := range t.msgs {
+		err := r.store.HoldSubmittedDepositTx(context.Background(), mariadb.CreateSwapReviewParams{
+			SenderTxHash: m.SenderTxHash,
+			Reason:       reason,
+		})
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msgf("cannot hold submitted deposit. hash:%s", m.SenderTxHash)
+			return err
+		}
+		r.c.Logger.Warn().Msgf("%s. hold deposit for manual review. hash:%s", reason, m.SenderTxHash)
+	}
+	return nil
+}
+
+// resolveTransfer는 receipt에 따라 deposit의 swap history를 저장하거나 재시도 대상으로 기록합니다.
+func (r *ReceiverChain) resolveTransfer(t *submittedTransfer, rec *types.Receipt) error {
+	if rec.Status == types.ReceiptStatusSuccessful {
+		for _, m := range t.msgs {
+			err := r.recordPayout(m, &t.hash)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, m := range t.msgs {
+		err := r.handleConfirmError(m, fmt.Errorf("%w. tx:%s", errTransferReverted, t.hash.Hex()))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetDeposits는 deposit을 다시 지급할 수 있도록 detected 상태로 되돌립니다.
+func (r *ReceiverChain) resetDeposits(msgs ...message.DepositMessage) error {
+	for _, m := range msgs {
+		err := r.store.ResetDeposit(context.Background(), m.SenderTxHash)
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msgf("cannot reset deposit. hash:%s", m.SenderTxHash)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store/mariadb"
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupSubmitted(t *testing.T) {
+	row := func(sender, receiverTx string, nonce int64) mariadb.BersDepositQueue {
+		return mariadb.BersDepositQueue{
+			SenderTxHash:    sender,
+			BlockHash:       common.HexToHash("0x01").Hex(),
+			SenderAddress:   common.HexToAddress("0x02").Hex(),
+			ReceiverAddress: common.HexToAddress("0x03").Hex(),
+			Amount:          "1000",
+			ReceiverTxHash:  sql.NullString{String: receiverTx, Valid: true},
+			ReceiverNonce:   sql.NullInt64{Int64: nonce, Valid: true},
+		}
+	}
+
+	batchTx := common.HexToHash("0xb0").Hex()
+	singleTx := common.HexToHash("0xa0").Hex()
+	submittedAt := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	late := row("0x13", batchTx, 5)
+	late.UpdatedAt = sql.NullTime{Time: submittedAt.Add(time.Minute), Valid: true}
+	early := row("0x11", batchTx, 5)
+	early.UpdatedAt = sql.NullTime{Time: submittedAt, Valid: true}
+	transfers, err := groupSubmitted([]mariadb.BersDepositQueue{
+		early,
+		row("0x12", singleTx, 4),
+		late,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+
+	require.Equal(t, singleTx, transfers[0].hash.Hex())
+	require.Equal(t, uint64(4), transfers[0].nonce)
+	require.Len(t, transfers[0].msgs, 1)
+
+	require.Equal(t, batchTx, transfers[1].hash.Hex())
+	require.Len(t, transfers[1].msgs, 2)
+	require.Equal(t, "0x11", transfers[1].msgs[0].SenderTxHash)
+	require.Equal(t, "0x13", transfers[1].msgs[1].SenderTxHash)
+	// 가장 늦게 submitted 상태가 된 시각부터 mempool에서 사라졌는지 판단한다.
+	require.Equal(t, submittedAt.Add(time.Minute), transfers[1].submittedAt)
+	require.True(t, transfers[0].submittedAt.IsZero())
+
+	_, err = groupSubmitted([]mariadb.BersDepositQueue{{SenderTxHash: "0x14", Amount: "1"}})
+	require.Error(t, err)
+}
+
+// 시작 시점에 찾을 수 없는 토큰 전송은 계정의 nonce가 지나지 않았을 때만 다시 지급하는가?
+func TestReconcileTransferNotFound(t *testing.T) {
+	client := newTestRPCClient(t, func(method string, params []json.RawMessage) interface{} {
+		if method == "eth_getTransactionCount" {
+			return hexutil.Uint64(5)
+		}
+		// receipt와 트랜잭션을 찾을 수 없다.
+		return nil
+	})
+
+	transfer := func(nonce uint64) *submittedTransfer {
+		m := message.NewDepositMessage(1, common.Hash{}, nonce, common.HexToAddress("0x1"), common.HexToAddress("0x2"), big.NewInt(1), common.BigToHash(new(big.Int).SetUint64(nonce)).Hex())
+		return &submittedTransfer{
+			hash:  common.BigToHash(big.NewInt(100)),
+			nonce: nonce,
+			msgs:  []message.DepositMessage{m},
+		}
+	}
+	newReceiver := func() (*ReceiverChain, *testDB) {
+		st, db := newTestStore(t)
+		return &ReceiverChain{
+			c:     &chain.Chain{EvmClient: client, Logger: zerolog.Nop()},
+			store: st,
+		}, db
+	}
+
+	// 계정의 nonce가 지나지 않았다면 다음 토큰 전송이 같은 nonce를 사용하므로 다시 지급한다.
+	r, db := newReceiver()
+	unsent := transfer(5)
+	require.NoError(t, r.reconcileTransfer(unsent, true))
+	reset := db.executed("ResetDeposit")
+	require.Len(t, reset, 1)
+	require.Equal(t, unsent.msgs[0].SenderTxHash, reset[0][0])
+	require.Empty(t, db.executed("HoldSubmittedDeposit"))
+
+	// 계정의 nonce가 지났다면 지급되었을 수 있으므로 held 상태로 보류한다.
+	r, db = newReceiver()
+	used := transfer(3)
+	require.NoError(t, r.reconcileTransfer(used, true))
+	require.Empty(t, db.executed("ResetDeposit"))
+	held := db.executed("HoldSubmittedDeposit")
+	require.Len(t, held, 1)
+	require.Contains(t, held[0][0], ReasonTransferNotFound)
+	require.Equal(t, used.msgs[0].SenderTxHash, held[0][1])
+	require.Len(t, db.executed("CreateSwapReview"), 1)
+}
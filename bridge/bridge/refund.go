@@ -0,0 +1,356 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/connection"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"berith-swap/bridge/transaction"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	RefundInterval     = time.Minute
+	RefundBatchSize    = int32(100)
+	DefaultRefundDelay = time.Hour
+)
+
+// refunder는 영구적으로 지급에 실패한 deposit의 BERS를 Berith chain의 owner 계정에서 sender에게 돌려보냅니다.
+// 재시도 횟수를 초과한 failed 상태와 운영자가 거절한 rejected 상태의 deposit이 환불 대상입니다.
+// 주소 screening에 걸린 deposit은 제재 대상 주소로 BERS를 돌려보내지 않도록 자동 환불하지 않습니다.
+// quorum 불일치, nonce 중복, reorg 등으로 보류되었던 deposit이 실제로 존재하지 않을 수 있으므로 환불 전에 항상 체인에서 deposit을 확인합니다.
+type refunder struct {
+	c          *chain.Chain
+	store      *store.Store
+	transactor transaction.Transactor
+	screener   *screener        // Receiver chain config의 denylist와 allowlist
+	verifier   *depositVerifier // 환불할 deposit이 체인에 존재하는지 확인
+	auto       bool             // 환불 대상 deposit을 주기적으로 자동 환불할지 여부
+	delay      time.Duration    // deposit이 환불 대상이 된 뒤 자동 환불까지 기다리는 시간
+}
+
+// newRefunder는 config로부터 refunder를 생성합니다.
+// 주소 screening은 Receiver chain config의 denylist와 allowlist를 사용하고, deposit 확인은 verifier를 사용합니다.
+func newRefunder(c *chain.Chain, st *store.Store, verifier *depositVerifier, cfg *config.Config, chainCfg *config.RawChainConfig) (*refunder, error) {
+	t, err := contract.InitializeTransactor(contract.BerithGasPrice, transaction.NewTransaction, c.EvmClient)
+	if err != nil {
+		return nil, err
+	}
+
+	delay, err := parseRefundDelay(chainCfg.RefundDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	screener := &screener{}
+	if len(cfg.ChainConfig) > ReceiverIdx {
+		screener, err = newScreener(cfg.ChainConfig[ReceiverIdx])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &refunder{
+		c:          c,
+		store:      st,
+		transactor: t,
+		screener:   screener,
+		verifier:   verifier,
+		auto:       chainCfg.AutoRefund,
+		delay:      delay,
+	}, nil
+}
+
+// Refund는 failed 또는 rejected 상태의 deposit을 sender에게 환불합니다.
+// 주소 screening에 걸린 deposit은 force가 true일 때만 환불하며, 체인에서 확인할 수 없는 deposit은 force와 관계없이 환불하지 않습니다.
+// 실행 중인 Bridge와 같은 계정으로 트랜잭션을 서명하므로 Bridge가 실행 중이라면 nonce 충돌을 막기 위해 환불하지 않습니다.
+func (b *Bridge) Refund(senderTxHash string, force bool) error {
+	err := b.lockSigners()
+	if err != nil {
+		return fmt.Errorf("cannot refund while bridge is running. enable autoRefund or stop the bridge and retry. err:%w", err)
+	}
+	defer b.unlockSigners()
+
+	row, err := b.sc.store.GetDeposit(context.Background(), senderTxHash)
+	if err != nil {
+		return fmt.Errorf("cannot get deposit from deposit queue. hash:%s, err:%w", senderTxHash, err)
+	}
+	if row.Direction != store.DirectionForward {
+		return fmt.Errorf("only BERS deposits can be refunded. hash:%s, direction:%s", senderTxHash, row.Direction)
+	}
+	if row.Status != store.DepositFailed && row.Status != store.DepositRejected {
+		return fmt.Errorf("deposit is not refundable. hash:%s, status:%s", senderTxHash, row.Status)
+	}
+	if reason, err := b.sc.refunder.screen(row); err != nil || reason != "" {
+		if err != nil {
+			return err
+		}
+		if !force {
+			return fmt.Errorf("deposit is screened. refund with --force after review. hash:%s, reason:%s", senderTxHash, reason)
+		}
+		b.sc.c.Logger.Warn().Msgf("refund screened deposit by operator. hash:%s, reason:%s", senderTxHash, reason)
+	}
+	return b.sc.refunder.refund(row)
+}
+
+// setRefunder는 config로부터 환불에 사용할 refunder를 설정합니다.
+// deposit 교차 검증이 설정되어 있다면 같은 quorum으로 환불할 deposit을 확인하고, 아니라면 sender chain의 endpoint로 확인합니다.
+func (s *SenderChain) setRefunder(cfg *config.Config, chainCfg *config.RawChainConfig) {
+	verifier := s.verifier
+	if verifier == nil && s.swapContract != nil {
+		verifier = &depositVerifier{
+			clients:      []*connection.EvmClient{s.c.EvmClient},
+			quorum:       1,
+			swapContract: s.swapContract,
+			signer:       types.LatestSignerForChainID(s.c.EvmClient.ChainId()),
+		}
+	}
+
+	r, err := newRefunder(s.c, s.store, verifier, cfg, chainCfg)
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msg("cannot init refunder")
+	}
+	if r.auto {
+		s.c.Logger.Info().Msgf("failed and rejected deposits will be refunded after %s", r.delay)
+	}
+	s.refunder = r
+}
+
+// watchRefunds는 주기적으로 전송된 환불을 정리하고, autoRefund가 설정되어 있다면 환불 대상 deposit을 환불합니다.
+// 환불에 실패하면 로그를 남기고 다음 주기에 다시 시도합니다.
+func (s *SenderChain) watchRefunds() {
+	ticker := time.NewTicker(RefundInterval)
+	defer ticker.Stop()
+
+	startup := true
+	for {
+		err := s.refunder.reconcile(startup)
+		if err != nil {
+			s.c.Logger.Error().Err(err).Msg("cannot reconcile submitted refunds. retry next interval")
+		} else {
+			startup = false
+			if s.refunder.auto {
+				if err := s.refunder.refundPending(); err != nil {
+					s.c.Logger.Error().Err(err).Msg("cannot get refundable deposits. retry next interval")
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// refundPending은 refundDelay 이상 failed 또는 rejected 상태로 남아있는 deposit을 환불합니다.
+// 주소 screening에 걸린 deposit은 환불하지 않고 운영자가 refund 명령으로 처리하도록 남겨둡니다.
+// 체인에서 확인할 수 없는 deposit은 환불하지 않고 로그를 남깁니다.
+func (r *refunder) refundPending() error {
+	r.screener.reload(&r.c.Logger)
+
+	rows, err := r.store.ListRefundableDeposits(context.Background(), mariadb.ListRefundableDepositsParams{
+		DelaySeconds: int64(r.delay / time.Second),
+		Limit:        RefundBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get refundable deposits. err:%w", err)
+	}
+	for _, row := range rows {
+		reason, err := r.screen(row)
+		if err == nil && reason != "" {
+			r.c.Logger.Warn().Msgf("skip refund of screened deposit. refund manually after review. hash:%s, reason:%s", row.SenderTxHash, reason)
+			continue
+		}
+		if err == nil {
+			err = r.refund(row)
+		}
+		if errors.Is(err, errDepositMismatch) {
+			r.c.Logger.Warn().Err(err).Msgf("skip refund of deposit not found on chain. review the deposit manually. hash:%s", row.SenderTxHash)
+			continue
+		}
+		if err != nil {
+			// 환불하지 못한 deposit은 다음 주기에 다시 시도하고 나머지 deposit을 계속 환불한다.
+			r.c.Logger.Error().Err(err).Msgf("cannot refund deposit. hash:%s", row.SenderTxHash)
+		}
+	}
+	return nil
+}
+
+// screen은 deposit이 주소 screening 사유로 보류되었거나 현재 목록으로 screening에 걸린다면 사유를 반환합니다.
+func (r *refunder) screen(row mariadb.BersDepositQueue) (string, error) {
+	if isScreeningReason(row.Reason.String) {
+		return row.Reason.String, nil
+	}
+	m, err := depositFromQueue(row)
+	if err != nil {
+		return "", err
+	}
+	return r.screener.screen(m), nil
+}
+
+// verify는 deposit 트랜잭션이 체인에 존재하고 저장된 deposit과 같은 Deposit 이벤트를 갖는지 확인합니다.
+// 체인의 deposit과 일치하지 않는다면 errDepositMismatch를 반환합니다.
+func (r *refunder) verify(row mariadb.BersDepositQueue) error {
+	if r.verifier == nil {
+		return errors.New("deposit verifier is not set")
+	}
+	m, err := depositFromQueue(row)
+	if err != nil {
+		return err
+	}
+	return r.verifier.verify(m)
+}
+
+// refund는 deposit의 sender에게 예치된 BERS를 owner 계정에서 전송하고 receipt를 기다립니다.
+// 체인에서 deposit을 확인한 뒤에만 환불하며, 트랜잭션을 전송하기 전에 환불을 기록하여 같은 deposit이 두 번 환불되지 않도록 합니다.
+func (r *refunder) refund(row mariadb.BersDepositQueue) error {
+	m, err := depositFromQueue(row)
+	if err != nil {
+		return err
+	}
+	if err := r.verify(row); err != nil {
+		return fmt.Errorf("cannot verify deposit on chain. refund is not allowed. hash:%s, reason:%s, err:%w", m.SenderTxHash, row.Reason.String, err)
+	}
+
+	balance, err := r.c.EvmClient.BalanceAt(context.Background(), r.c.EvmClient.From(), nil)
+	if err != nil {
+		return fmt.Errorf("cannot get owner balance. err:%w", err)
+	}
+	if balance.Cmp(m.Amount) < 0 {
+		return fmt.Errorf("insufficient owner balance for refund. hash:%s, balance:%s, required:%s", m.SenderTxHash, balance.String(), m.Amount.String())
+	}
+
+	refund := mariadb.CreateRefundParams{
+		SenderTxHash:  m.SenderTxHash,
+		SenderAddress: m.Sender.Hex(),
+		Amount:        m.Amount.String(),
+		DepositStatus: row.Status,
+	}
+	opts := transaction.TransactOptions{
+		GasLimit: r.c.GasLimit.Uint64(),
+		Value:    m.Amount,
+		OnSigned: func(hash common.Hash, nonce uint64) error {
+			refund.TxHash = hash.Hex()
+			refund.Nonce = int64(nonce)
+			return r.store.RefundDepositTx(context.Background(), refund)
+		},
+	}
+	txHash, err := r.transactor.Submit(&m.Sender, nil, opts)
+	if err != nil {
+		// 환불이 기록된 뒤 전송에 실패했다면 트랜잭션이 노드에 전달되었을 수 있으므로 reconcile에서 확인한다.
+		return fmt.Errorf("cannot submit refund. hash:%s, err:%w", m.SenderTxHash, err)
+	}
+	r.c.Logger.Info().Msgf("submitted refund. sender tx:%s, sender:%s, value:%s, Tx Hash:%s", m.SenderTxHash, m.Sender.Hex(), m.Amount.String(), txHash.Hex())
+
+	rec, err := r.c.EvmClient.WaitAndReturnTxReceipt(*txHash)
+	if err != nil && (rec == nil || rec.Status != types.ReceiptStatusFailed) {
+		r.c.Logger.Warn().Err(err).Msgf("refund is not confirmed yet. reconcile later. hash:%s, tx:%s", m.SenderTxHash, txHash.Hex())
+		return nil
+	}
+	return r.resolve(mariadb.BersRefund{
+		SenderTxHash:  refund.SenderTxHash,
+		TxHash:        refund.TxHash,
+		DepositStatus: refund.DepositStatus,
+	}, rec)
+}
+
+// reconcile은 submitted 상태로 남은 환불 트랜잭션을 체인의 상태와 비교하여 정리합니다.
+// 트랜잭션이 체인에 존재하지 않는데 계정의 nonce가 이미 해당 nonce를 지났다면 운영자가 확인하도록 submitted 상태로 남겨두고,
+// 지나지 않았고 막 시작되었다면 환불을 failed로 변경하여 다시 환불할 수 있도록 합니다.
+// DroppedTransferTimeout이 지나도록 찾을 수 없는 환불은 mempool에서 사라진 것으로 보고 계정의 nonce를 다시 조회한 뒤 다시 환불할 수 있도록 합니다.
+func (r *refunder) reconcile(startup bool) error {
+	refunds, err := r.store.ListRefundsByStatus(context.Background(), store.RefundSubmitted)
+	if err != nil {
+		return fmt.Errorf("cannot get submitted refunds. err:%w", err)
+	}
+
+	for _, refund := range refunds {
+		hash := common.HexToHash(refund.TxHash)
+		rec, err := r.c.EvmClient.TransactionReceipt(context.Background(), hash)
+		if err == nil {
+			if err := r.resolve(refund, rec); err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("cannot get receipt of refund. tx:%s, err:%w", refund.TxHash, err)
+		}
+
+		_, _, err = r.c.EvmClient.GetTransactionByHash(hash)
+		if err == nil {
+			r.c.Logger.Info().Msgf("refund is pending. tx:%s", refund.TxHash)
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("cannot get refund transaction. tx:%s, err:%w", refund.TxHash, err)
+		}
+
+		nonce, err := r.c.EvmClient.NonceAt(context.Background(), r.c.EvmClient.From(), nil)
+		if err != nil {
+			return fmt.Errorf("cannot get account nonce. err:%w", err)
+		}
+		switch {
+		case uint64(refund.Nonce) < nonce:
+			// 노드가 트랜잭션을 찾지 못할 뿐 환불되었을 수 있으므로 다시 환불하지 않고 submitted 상태로 남겨둔다.
+			r.c.Logger.Error().Msgf("refund not found after its nonce was used. check the refund manually. sender tx:%s, tx:%s, nonce:%d, account nonce:%d", refund.SenderTxHash, refund.TxHash, refund.Nonce, nonce)
+			continue
+		case startup:
+			r.c.Logger.Warn().Msgf("refund was not broadcast. tx:%s, nonce:%d", refund.TxHash, refund.Nonce)
+		case refund.UpdatedAt.Valid && time.Since(refund.UpdatedAt.Time) >= DroppedTransferTimeout:
+			r.c.Logger.Warn().Msgf("refund was dropped from mempool. refund again. tx:%s, nonce:%d, submitted at:%s", refund.TxHash, refund.Nonce, refund.UpdatedAt.Time)
+			r.c.EvmClient.ResetNonce()
+		default:
+			r.c.Logger.Warn().Msgf("refund not found. tx:%s, nonce:%d", refund.TxHash, refund.Nonce)
+			continue
+		}
+		if err := r.store.FailRefundTx(context.Background(), refund); err != nil {
+			return fmt.Errorf("cannot reset refund. hash:%s, err:%w", refund.SenderTxHash, err)
+		}
+	}
+	return nil
+}
+
+// resolve는 환불 트랜잭션의 receipt에 따라 환불을 완료하거나 다시 환불할 수 있도록 되돌립니다.
+func (r *refunder) resolve(refund mariadb.BersRefund, rec *types.Receipt) error {
+	if rec.Status == types.ReceiptStatusSuccessful {
+		err := r.store.CompleteRefundTx(context.Background(), refund.SenderTxHash)
+		if err != nil {
+			return fmt.Errorf("cannot complete refund. hash:%s, err:%w", refund.SenderTxHash, err)
+		}
+		gasUsed := new(big.Int).SetUint64(rec.GasUsed)
+		r.c.Logger.Info().Msgf("refunded deposit. sender tx:%s, Tx Hash:%s, GasUsed:%s", refund.SenderTxHash, refund.TxHash, gasUsed.String())
+		return nil
+	}
+
+	err := r.store.FailRefundTx(context.Background(), refund)
+	if err != nil {
+		return fmt.Errorf("cannot reset refund. hash:%s, err:%w", refund.SenderTxHash, err)
+	}
+	r.c.Logger.Error().Msgf("refund reverted. sender tx:%s, Tx Hash:%s", refund.SenderTxHash, refund.TxHash)
+	return nil
+}
+
+// parseRefundDelay는 자동 환불까지 기다리는 시간을 파싱합니다. 설정되지 않았다면 DefaultRefundDelay를 사용합니다.
+func parseRefundDelay(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultRefundDelay, nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil || delay < 0 {
+		return 0, fmt.Errorf("invalid refundDelay:%s", value)
+	}
+	return delay, nil
+}
@@ -0,0 +1,150 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRefundDelay(t *testing.T) {
+	delay, err := parseRefundDelay("")
+	require.NoError(t, err)
+	require.Equal(t, DefaultRefundDelay, delay)
+
+	delay, err = parseRefundDelay("30m")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, delay)
+
+	delay, err = parseRefundDelay("0s")
+	require.NoError(t, err)
+	require.Zero(t, delay)
+
+	_, err = parseRefundDelay("-1h")
+	require.Error(t, err)
+
+	_, err = parseRefundDelay("soon")
+	require.Error(t, err)
+}
+
+// 주소 screening에 걸렸거나 screening 사유로 거절된 deposit을 환불 대상에서 구분하는가?
+func TestRefunderScreen(t *testing.T) {
+	denied := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	denyPath := filepath.Join(t.TempDir(), "denylist.txt")
+	require.NoError(t, os.WriteFile(denyPath, []byte(denied.Hex()+"\n"), 0644))
+	s, err := newScreener(&config.RawChainConfig{DenylistPath: denyPath})
+	require.NoError(t, err)
+	r := &refunder{screener: s}
+
+	row := func(sender common.Address, reason string) mariadb.BersDepositQueue {
+		return mariadb.BersDepositQueue{
+			SenderTxHash:    "0x11",
+			BlockHash:       common.HexToHash("0x01").Hex(),
+			SenderAddress:   sender.Hex(),
+			ReceiverAddress: other.Hex(),
+			Amount:          "1000",
+			Reason:          sql.NullString{String: reason, Valid: reason != ""},
+		}
+	}
+
+	reason, err := r.screen(row(other, "max retries exceeded"))
+	require.NoError(t, err)
+	require.Empty(t, reason)
+
+	reason, err = r.screen(row(denied, ""))
+	require.NoError(t, err)
+	require.Contains(t, reason, ReasonDenylistedSender)
+
+	// 목록에서 제외되었더라도 screening 사유로 보류되었던 deposit은 자동 환불하지 않는다.
+	reason, err = r.screen(row(other, ReasonNotAllowlistedReceiver+". address:"+other.Hex()))
+	require.NoError(t, err)
+	require.Contains(t, reason, ReasonNotAllowlistedReceiver)
+}
+
+// 체인의 Deposit 이벤트와 일치하지 않는 deposit을 환불하지 않는가?
+func TestRefunderVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	swap := newTestSwapContract(nil)
+	d := newTestDeposit(t, swap, key, 1, 100)
+	missing := newTestDeposit(t, swap, key, 2, 101)
+
+	r := &refunder{verifier: newTestVerifier(swap, 1, newTestDepositClient(t, nil, d))}
+	row := func(m message.DepositMessage) mariadb.BersDepositQueue {
+		return mariadb.BersDepositQueue{
+			SenderTxHash:    m.SenderTxHash,
+			BlockNumber:     int64(m.BlockNumber),
+			BlockHash:       m.BlockHash.Hex(),
+			DepositNonce:    int64(m.DepositNonce),
+			SenderAddress:   m.Sender.Hex(),
+			ReceiverAddress: m.Receiver.Hex(),
+			Amount:          m.Amount.String(),
+			Status:          store.DepositRejected,
+		}
+	}
+
+	require.NoError(t, r.verify(row(d.msg)))
+
+	// 체인에 존재하지 않는 deposit은 환불하지 않는다.
+	require.ErrorIs(t, r.verify(row(missing.msg)), errDepositMismatch)
+	require.ErrorIs(t, r.refund(row(missing.msg)), errDepositMismatch)
+
+	// 저장된 금액이 체인의 deposit과 다르다면 환불하지 않는다.
+	tampered := row(d.msg)
+	tampered.Amount = "1"
+	require.ErrorIs(t, r.verify(tampered), errDepositMismatch)
+
+	// deposit을 확인할 수 없다면 환불하지 않는다.
+	require.Error(t, (&refunder{}).verify(row(d.msg)))
+}
+
+// 시작 시점에 찾을 수 없는 환불은 계정의 nonce가 지나지 않았을 때만 다시 환불하는가?
+func TestRefunderReconcileNotFound(t *testing.T) {
+	client := newTestRPCClient(t, func(method string, params []json.RawMessage) interface{} {
+		if method == "eth_getTransactionCount" {
+			return hexutil.Uint64(5)
+		}
+		// receipt와 트랜잭션을 찾을 수 없다.
+		return nil
+	})
+
+	refund := func(nonce int64) mariadb.BersRefund {
+		return mariadb.BersRefund{
+			SenderTxHash:  common.BigToHash(big.NewInt(nonce)).Hex(),
+			TxHash:        common.BigToHash(big.NewInt(100 + nonce)).Hex(),
+			Nonce:         nonce,
+			DepositStatus: store.DepositFailed,
+			Status:        store.RefundSubmitted,
+		}
+	}
+	unsent, used := refund(5), refund(3)
+	st, db := newTestStore(t)
+	db.refunds = []mariadb.BersRefund{used, unsent}
+	r := &refunder{
+		c:     &chain.Chain{EvmClient: client, Logger: zerolog.Nop()},
+		store: st,
+	}
+	require.NoError(t, r.reconcile(true))
+
+	// 계정의 nonce가 지났다면 환불되었을 수 있으므로 다시 환불하지 않는다.
+	failed := db.executed("UpdateRefundStatus")
+	require.Len(t, failed, 1)
+	require.Equal(t, []driver.Value{store.RefundFailed, unsent.SenderTxHash}, failed[0])
+}
@@ -0,0 +1,178 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// BlockRecordWindow는 reorg 감지를 위해 보관하는 최근 블록 record의 최대 개수입니다.
+	BlockRecordWindow = 128
+)
+
+// errDeepReorg는 보관한 모든 block record가 canonical chain과 일치하지 않아 공통 조상을 찾을 수 없을 때 반환됩니다.
+var errDeepReorg = errors.New("reorg is deeper than stored block records")
+
+// checkReorg는 currentBlock의 부모 해시가 마지막으로 처리한 블록의 해시와 일치하는지 확인합니다.
+// reorg가 감지되면 currentBlock을 공통 조상 블록의 다음 블록으로 되돌리고 true를 반환합니다.
+func (s *SenderChain) checkReorg(currentBlock *big.Int) (*types.Header, bool, error) {
+	header, err := s.c.EvmClient.HeaderByNumber(context.Background(), currentBlock)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot get header. number:%s, err:%w", currentBlock.String(), err)
+	}
+	if len(s.blockRecords) == 0 {
+		return header, false, nil
+	}
+
+	last := s.blockRecords[len(s.blockRecords)-1]
+	canonical := header.ParentHash
+	if last.Number+1 != currentBlock.Uint64() {
+		lastHeader, err := s.c.EvmClient.HeaderByNumber(context.Background(), new(big.Int).SetUint64(last.Number))
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot get header. number:%d, err:%w", last.Number, err)
+		}
+		canonical = lastHeader.Hash()
+	}
+	if canonical == last.Hash {
+		return header, false, nil
+	}
+
+	s.c.Logger.Warn().Msgf("chain reorganization detected. block:%d, stored:%s, canonical:%s", last.Number, last.Hash.Hex(), canonical.Hex())
+	return nil, true, s.rewind(currentBlock)
+}
+
+// rewind는 저장된 block record 중 canonical chain과 일치하는 가장 최근 블록을 찾아 그 다음 블록으로 되돌아갑니다.
+// 되돌린 구간에서 감지되었던 deposit 중 canonical chain에서 사라진 트랜잭션은 store에 검토 대상으로 기록합니다.
+// 모든 record가 canonical chain과 일치하지 않는다면 기록된 deposit을 검토한 뒤 errDeepReorg를 반환하며,
+// 운영자가 reorg 이전 블록을 --start-block으로 지정하여 다시 시작해야 합니다.
+func (s *SenderChain) rewind(currentBlock *big.Int) error {
+	ancestor := -1
+	for i := len(s.blockRecords) - 1; i >= 0; i-- {
+		rec := s.blockRecords[i]
+		header, err := s.c.EvmClient.HeaderByNumber(context.Background(), new(big.Int).SetUint64(rec.Number))
+		if err != nil {
+			return fmt.Errorf("cannot get header. number:%d, err:%w", rec.Number, err)
+		}
+		if header.Hash() == rec.Hash {
+			ancestor = i
+			break
+		}
+	}
+
+	dropped := s.blockRecords[ancestor+1:]
+	for _, rec := range dropped {
+		for _, hash := range rec.Deposits {
+			if err := s.reviewDeposit(hash); err != nil {
+				return err
+			}
+		}
+	}
+
+	if ancestor < 0 {
+		// 가장 오래된 record도 canonical chain에서 벗어났으므로 어디까지 되돌려야 하는지 알 수 없다.
+		return fmt.Errorf("%w. oldest record:%d. restart with --start-block before the reorg", errDeepReorg, s.blockRecords[0].Number)
+	}
+	rewindTo := s.blockRecords[ancestor].Number + 1
+
+	s.blockRecords = s.blockRecords[:ancestor+1]
+	if err := s.blockStore.StoreBlockRecords(s.blockRecords); err != nil {
+		s.c.Logger.Error().Err(err).Msg("Failed to write block records to blockstore")
+	}
+
+	s.c.Logger.Info().Msgf("rewind sender chain from %s to %d", currentBlock.String(), rewindTo)
+	currentBlock.SetUint64(rewindTo)
+	return nil
+}
+
+// reviewDeposit은 reorg로 되돌린 deposit 트랜잭션이 canonical chain에 남아있는지 확인합니다.
+// 트랜잭션이 사라졌다면 지급 여부와 함께 store에 검토 대상으로 기록합니다.
+// 아직 지급 트랜잭션이 서명되지 않은 deposit은 held 상태가 되어 지급되지 않지만,
+// 이미 지급 트랜잭션이 서명되었거나 지급된 deposit은 되돌릴 수 없으므로 에러 로그로 알립니다.
+func (s *SenderChain) reviewDeposit(hash string) error {
+	receipt, err := s.c.EvmClient.TransactionReceipt(context.Background(), common.HexToHash(hash))
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("cannot get deposit receipt. hash:%s, err:%w", hash, err)
+	}
+	if err == nil && receipt.Status == types.ReceiptStatusSuccessful {
+		s.c.Logger.Debug().Msgf("deposit still exists after reorg. hash:%s, block:%s", hash, receipt.BlockNumber.String())
+		return nil
+	}
+
+	reason := "deposit vanished after reorg"
+	row, err := s.store.GetDeposit(context.Background(), hash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("cannot get deposit from deposit queue. hash:%s, err:%w", hash, err)
+	}
+	hist, err := s.store.GetBersSwapHistory(context.Background(), hash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("cannot get swap history. hash:%s, err:%w", hash, err)
+	}
+	paid := hist.SenderTxHash != "" || row.Status == store.DepositSubmitted || row.Status == store.DepositConfirmed
+	if paid {
+		reason = "paid deposit vanished after reorg"
+	}
+
+	err = s.flagDeposit(hash, reason)
+	if err != nil {
+		return err
+	}
+	if paid {
+		s.c.Logger.Error().Msgf("payout of vanished deposit cannot be stopped. recover the payout manually. hash:%s, status:%s, payout tx:%s", hash, row.Status, row.ReceiverTxHash.String)
+	}
+	return nil
+}
+
+// flagDeposit은 deposit을 store에 수동 검토 대상으로 기록하고 지급되지 않은 deposit은 보류합니다.
+func (s *SenderChain) flagDeposit(hash, reason string) error {
+	err := s.store.HoldDepositTx(context.Background(), mariadb.CreateSwapReviewParams{
+		SenderTxHash: hash,
+		Reason:       reason,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot store swap review. hash:%s, err:%w", hash, err)
+	}
+	s.c.Logger.Error().Msgf("%s. flagged for manual review. hash:%s", reason, hash)
+	return nil
+}
+
+// recordBlock은 처리를 마친 블록의 해시와 감지된 deposit을 기록합니다.
+func (s *SenderChain) recordBlock(header *types.Header, msgs []message.DepositMessage) {
+	deposits := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		deposits = append(deposits, m.SenderTxHash)
+	}
+
+	s.blockRecords = append(s.blockRecords, blockstore.BlockRecord{
+		Number:   header.Number.Uint64(),
+		Hash:     header.Hash(),
+		Deposits: deposits,
+	})
+	if len(s.blockRecords) > BlockRecordWindow {
+		s.blockRecords = s.blockRecords[len(s.blockRecords)-BlockRecordWindow:]
+	}
+
+	if err := s.blockStore.StoreBlockRecords(s.blockRecords); err != nil {
+		s.c.Logger.Error().Err(err).Msg("Failed to write block records to blockstore")
+	}
+}
+
+// trimBlockRecords는 startBlock 이후의 record를 제거합니다. 해당 블록들은 다시 탐색됩니다.
+func trimBlockRecords(records []blockstore.BlockRecord, startBlock *big.Int) []blockstore.BlockRecord {
+	for i, rec := range records {
+		if rec.Number >= startBlock.Uint64() {
+			return records[:i]
+		}
+	}
+	return records
+}
@@ -0,0 +1,117 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/chain"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// 다시 탐색할 블록의 record가 제거되는가?
+func TestTrimBlockRecords(t *testing.T) {
+	records := []blockstore.BlockRecord{{Number: 10}, {Number: 20}, {Number: 30}}
+
+	require.Len(t, trimBlockRecords(records, big.NewInt(31)), 3)
+	require.Len(t, trimBlockRecords(records, big.NewInt(30)), 2)
+	require.Len(t, trimBlockRecords(records, big.NewInt(11)), 1)
+	require.Len(t, trimBlockRecords(records, big.NewInt(1)), 0)
+}
+
+// 조회 도중 마지막 블록이 바뀌었다면 감지하는가?
+func TestIsSameBlockHash(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(100)}
+
+	logs := []types.Log{
+		{BlockNumber: 99, BlockHash: common.HexToHash("0x01")},
+		{BlockNumber: 100, BlockHash: header.Hash()},
+	}
+	require.True(t, isSameBlockHash(logs, header))
+
+	logs = append(logs, types.Log{BlockNumber: 100, BlockHash: common.HexToHash("0x02")})
+	require.False(t, isSameBlockHash(logs, header))
+}
+
+// newTestReorgChain은 canonical header로 eth_getBlockByNumber에 응답하는 SenderChain을 생성합니다.
+func newTestReorgChain(t *testing.T, canonical map[uint64]*types.Header, records []blockstore.BlockRecord) *SenderChain {
+	client := newTestRPCClient(t, func(method string, params []json.RawMessage) interface{} {
+		if method != "eth_getBlockByNumber" {
+			return nil
+		}
+		var number hexutil.Big
+		require.NoError(t, json.Unmarshal(params[0], &number))
+		return canonical[number.ToInt().Uint64()]
+	})
+
+	bs, err := blockstore.NewBlockstore(t.TempDir(), "test")
+	require.NoError(t, err)
+
+	return &SenderChain{
+		c:            &chain.Chain{EvmClient: client, Logger: zerolog.Nop()},
+		blockStore:   bs,
+		blockRecords: records,
+	}
+}
+
+// newTestHeaders는 from부터 to까지 부모 해시로 연결된 header를 생성합니다. difficulty로 서로 다른 fork를 만듭니다.
+func newTestHeaders(from, to uint64, parent common.Hash, difficulty int64) map[uint64]*types.Header {
+	headers := make(map[uint64]*types.Header)
+	for n := from; n <= to; n++ {
+		h := &types.Header{Number: new(big.Int).SetUint64(n), ParentHash: parent, Difficulty: big.NewInt(difficulty)}
+		headers[n] = h
+		parent = h.Hash()
+	}
+	return headers
+}
+
+// 부모 해시가 마지막 record와 다르면 공통 조상의 다음 블록으로 되돌아가는가?
+func TestRewindAfterParentHashMismatch(t *testing.T) {
+	canonical := newTestHeaders(10, 13, common.Hash{}, 0)
+	forked := newTestHeaders(12, 12, canonical[11].Hash(), 1)
+
+	s := newTestReorgChain(t, canonical, []blockstore.BlockRecord{
+		{Number: 10, Hash: canonical[10].Hash()},
+		{Number: 11, Hash: canonical[11].Hash()},
+		{Number: 12, Hash: forked[12].Hash()},
+	})
+
+	currentBlock := big.NewInt(13)
+	header, reorged, err := s.checkReorg(currentBlock)
+	require.NoError(t, err)
+	require.True(t, reorged)
+	require.Nil(t, header)
+	require.Equal(t, uint64(12), currentBlock.Uint64())
+	require.Len(t, s.blockRecords, 2)
+	require.Equal(t, canonical[11].Hash(), s.blockRecords[1].Hash)
+
+	// 되돌린 뒤에는 canonical chain을 따라 계속 탐색한다.
+	header, reorged, err = s.checkReorg(currentBlock)
+	require.NoError(t, err)
+	require.False(t, reorged)
+	require.Equal(t, canonical[12].Hash(), header.Hash())
+}
+
+// 모든 record가 canonical chain과 다르다면 되돌리지 않고 멈추는가?
+func TestRewindDeeperThanRecords(t *testing.T) {
+	canonical := newTestHeaders(10, 13, common.Hash{}, 0)
+	forked := newTestHeaders(10, 12, common.Hash{}, 1)
+
+	records := []blockstore.BlockRecord{
+		{Number: 10, Hash: forked[10].Hash()},
+		{Number: 11, Hash: forked[11].Hash()},
+		{Number: 12, Hash: forked[12].Hash()},
+	}
+	s := newTestReorgChain(t, canonical, records)
+
+	currentBlock := big.NewInt(13)
+	_, _, err := s.checkReorg(currentBlock)
+	require.ErrorIs(t, err, errDeepReorg)
+	require.Equal(t, uint64(13), currentBlock.Uint64())
+	require.Len(t, s.blockRecords, len(records))
+}
@@ -0,0 +1,169 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	DefaultMaxRetries      = 5
+	DefaultRetryBackoff    = time.Second * 30
+	DefaultMaxRetryBackoff = time.Hour
+
+	errTransferReverted    = errors.New("token transfer reverted")
+	errTransferUnconfirmed = errors.New("token transfer unconfirmed")
+)
+
+// retryPolicy는 지급에 실패한 deposit의 최대 재시도 횟수와 재시도 간격을 결정합니다.
+type retryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// delay는 retryCount 번째 재시도까지 기다릴 시간을 반환합니다.
+// 재시도 간격은 backoff부터 두 배씩 늘어나며 maxBackoff를 넘지 않습니다.
+func (p retryPolicy) delay(retryCount int) time.Duration {
+	d := p.backoff
+	for i := 1; i < retryCount && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	return d
+}
+
+// newRetryPolicy는 config로부터 지급 재시도 정책을 생성합니다. 설정되지 않은 값은 기본값을 사용합니다.
+func newRetryPolicy(chainCfg *config.RawChainConfig, logger *zerolog.Logger) retryPolicy {
+	maxRetries, err := strconv.Atoi(chainCfg.MaxRetries)
+	if err != nil || maxRetries < 0 {
+		logger.Info().Msgf("max retries is not set. set default:%d", DefaultMaxRetries)
+		maxRetries = DefaultMaxRetries
+	}
+
+	backoff, err := time.ParseDuration(chainCfg.RetryBackoff)
+	if err != nil || backoff <= 0 {
+		logger.Info().Msgf("retry backoff is not set. set default:%s", DefaultRetryBackoff)
+		backoff = DefaultRetryBackoff
+	}
+
+	maxBackoff, err := time.ParseDuration(chainCfg.MaxRetryBackoff)
+	if err != nil || maxBackoff <= 0 {
+		logger.Info().Msgf("max retry backoff is not set. set default:%s", DefaultMaxRetryBackoff)
+		maxBackoff = DefaultMaxRetryBackoff
+	}
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+
+	return retryPolicy{
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+	}
+}
+
+// handleSubmitError는 토큰 전송 트랜잭션을 전송하지 못한 deposit을 재시도 대상으로 기록합니다.
+// 서명된 트랜잭션이 submitted 상태로 저장된 뒤 전송에 실패했다면 트랜잭션이 노드에 전달되었을 수 있으므로
+// 상태를 유지하고 reconcile에서 체인의 상태를 확인합니다.
+func (r *ReceiverChain) handleSubmitError(m message.DepositMessage, cause error) error {
+	if errors.Is(cause, errReceiverStopped) {
+		return cause
+	}
+	if errors.Is(cause, errInsufficientLiquidity) {
+		r.c.Logger.Warn().Err(cause).Msgf("hold deposit in queue until receiver balance is refilled. hash:%s", m.SenderTxHash)
+		return nil
+	}
+
+	row, err := r.store.GetDeposit(context.Background(), m.SenderTxHash)
+	if err != nil {
+		r.c.Logger.Error().Err(err).Msgf("cannot get deposit from deposit queue. hash:%s", m.SenderTxHash)
+		return err
+	}
+	if row.Status == store.DepositSubmitted {
+		r.c.Logger.Warn().Err(cause).Msgf("signed transfer may have been broadcast. reconcile later. hash:%s, tx:%s", m.SenderTxHash, row.ReceiverTxHash.String)
+		return nil
+	}
+	if errors.Is(cause, store.ErrDepositNotDetected) {
+		// 보류되었거나 다른 곳에서 처리 중인 deposit은 재시도하지 않고, 같은 batch의 detected deposit은 다음 주기에 다시 지급한다.
+		r.c.Logger.Warn().Err(cause).Msgf("deposit is no longer payable. skip transfer. hash:%s, status:%s", m.SenderTxHash, row.Status)
+		return nil
+	}
+	return r.retryDeposit(m, row.RetryCount, cause)
+}
+
+// handleConfirmError는 receipt 확인 결과에 따라 deposit을 재시도 대상으로 기록합니다.
+// receipt를 확인하지 못한 트랜잭션은 이후 체인에 포함될 수 있으므로 submitted 상태로 두고 reconcile에서 확인합니다.
+func (r *ReceiverChain) handleConfirmError(m message.DepositMessage, cause error) error {
+	switch {
+	case cause == nil:
+		return nil
+	case errors.Is(cause, errTransferUnconfirmed):
+		r.c.Logger.Warn().Err(cause).Msgf("token transfer is not confirmed yet. reconcile later. hash:%s", m.SenderTxHash)
+		return nil
+	case errors.Is(cause, errTransferReverted):
+		// 토큰이 pause 되어 revert 되었다면 재시도 횟수를 늘리지 않고 pause가 해제될 때까지 queue에 남겨둔다.
+		if paused, err := r.refreshPause(); err == nil && paused {
+			r.c.Logger.Warn().Err(cause).Msgf("token is paused. park deposit until unpaused. hash:%s", m.SenderTxHash)
+			return r.resetDeposits(m)
+		}
+		row, err := r.store.GetDeposit(context.Background(), m.SenderTxHash)
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msgf("cannot get deposit from deposit queue. hash:%s", m.SenderTxHash)
+			return err
+		}
+		return r.retryDeposit(m, row.RetryCount, cause)
+	default:
+		return cause
+	}
+}
+
+// retryDeposit은 receiver의 재시도 정책으로 지급에 실패한 deposit의 재시도를 기록합니다.
+func (r *ReceiverChain) retryDeposit(m message.DepositMessage, retryCount int32, cause error) error {
+	return r.retry.retryDeposit(r.store, &r.c.Logger, m.SenderTxHash, retryCount, cause)
+}
+
+// retryDeposit은 지급에 실패한 deposit의 에러를 기록하고 backoff 이후 다시 지급되도록 합니다.
+// 재시도 횟수를 초과했다면 deposit을 dead letter 테이블로 옮깁니다.
+func (p retryPolicy) retryDeposit(st *store.Store, logger *zerolog.Logger, senderTxHash string, retryCount int32, cause error) error {
+	retryCount++
+	lastError := sql.NullString{String: cause.Error(), Valid: true}
+
+	if int(retryCount) > p.maxRetries {
+		err := st.DeadLetterDepositTx(context.Background(), mariadb.RecordDepositErrorParams{
+			RetryCount:   retryCount,
+			LastError:    lastError,
+			SenderTxHash: senderTxHash,
+		})
+		if err != nil {
+			logger.Error().Err(err).Msgf("cannot move deposit to dead letter. hash:%s", senderTxHash)
+			return err
+		}
+		logger.Error().Err(cause).Msgf("retry limit exceeded. moved deposit to dead letter. hash:%s, retries:%d", senderTxHash, retryCount-1)
+		return nil
+	}
+
+	delay := p.delay(int(retryCount))
+	err := st.ScheduleDepositRetry(context.Background(), mariadb.ScheduleDepositRetryParams{
+		RetryCount:     retryCount,
+		LastError:      lastError,
+		BackoffSeconds: int64(delay / time.Second),
+		SenderTxHash:   senderTxHash,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msgf("cannot schedule deposit retry. hash:%s", senderTxHash)
+		return err
+	}
+	logger.Warn().Err(cause).Msgf("deposit payout failed. retry after %s. hash:%s, retry:%d/%d", delay, senderTxHash, retryCount, p.maxRetries)
+	return nil
+}
@@ -0,0 +1,36 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := retryPolicy{
+		maxRetries: 5,
+		backoff:    time.Second * 30,
+		maxBackoff: time.Minute * 3,
+	}
+
+	require.Equal(t, time.Second*30, p.delay(1))
+	require.Equal(t, time.Minute, p.delay(2))
+	require.Equal(t, time.Minute*2, p.delay(3))
+	require.Equal(t, time.Minute*3, p.delay(4))
+	require.Equal(t, time.Minute*3, p.delay(10))
+}
+
+// 설정되지 않았거나 잘못된 재시도 설정은 기본값을 사용하는가?
+func TestNewRetryPolicy(t *testing.T) {
+	logger := zerolog.Nop()
+
+	p := newRetryPolicy(&config.RawChainConfig{MaxRetries: "-1", RetryBackoff: "abc"}, &logger)
+	require.Equal(t, retryPolicy{maxRetries: DefaultMaxRetries, backoff: DefaultRetryBackoff, maxBackoff: DefaultMaxRetryBackoff}, p)
+
+	// 상한이 첫 재시도 간격보다 작다면 첫 재시도 간격을 상한으로 사용한다.
+	p = newRetryPolicy(&config.RawChainConfig{MaxRetries: "0", RetryBackoff: "2m", MaxRetryBackoff: "1m"}, &logger)
+	require.Equal(t, retryPolicy{maxRetries: 0, backoff: time.Minute * 2, maxBackoff: time.Minute * 2}, p)
+}
@@ -0,0 +1,271 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/util"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 자동으로 지급하지 않고 보류한 토큰 반환의 사유
+const (
+	ReasonMultipleReverseSenders = "multiple token senders in one transaction"
+	ReasonNoReverseRecipient     = "berith recipient not found in calldata"
+)
+
+// reverseWatcher는 Klaytn chain에서 reverseAddress로 전송된 토큰의 Transfer 이벤트를 감지하여
+// deposit queue에 reverse 방향의 deposit으로 저장합니다. reverseAddress가 zero address라면 토큰의 burn을 감지합니다.
+// BERS를 지급받을 Berith 주소는 사용자가 토큰 반환 트랜잭션의 calldata에 덧붙인 주소를 사용합니다.
+type reverseWatcher struct {
+	c                  *chain.Chain
+	token              *contract.ERC20Contract
+	store              *store.Store
+	blockStore         *blockstore.Blockstore
+	address            common.Address // 토큰을 반환받는 주소
+	confirmation       string
+	blockConfirmations *big.Int
+	blockRange         *big.Int
+	currentBlock       *big.Int // 다음에 탐색할 블록
+}
+
+// setReverseWatcher는 reverseAddress가 설정되어 있다면 토큰 반환을 감지할 reverseWatcher를 설정합니다.
+// 토큰 잔액을 채우는 전송이 반환으로 감지되지 않도록 reverseAddress는 owner 계정과 달라야 합니다.
+func (r *ReceiverChain) setReverseWatcher(cfg *config.Config, chainCfg *config.RawChainConfig) {
+	if chainCfg.ReverseAddress == "" {
+		return
+	}
+	if !common.IsHexAddress(chainCfg.ReverseAddress) {
+		r.c.Logger.Panic().Msgf("invalid reverseAddress:%s", chainCfg.ReverseAddress)
+	}
+	address := common.HexToAddress(chainCfg.ReverseAddress)
+	if address == r.c.EvmClient.From() {
+		r.c.Logger.Panic().Msg("reverseAddress must not be the owner account")
+	}
+
+	confirmation, err := parseConfirmationStrategy(chainCfg.ConfirmationStrategy)
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msg("check config.json")
+	}
+	if confirmation == ConfirmBySafe || confirmation == ConfirmByFinalized {
+		if _, err := r.c.EvmClient.TaggedBlockNumber(confirmation); err != nil {
+			r.c.Logger.Panic().Err(err).Msgf("endpoint dosen't support %s block tag", confirmation)
+		}
+	}
+
+	blockConfirmations, err := util.StringToBig(chainCfg.BlockConfirmations, 10)
+	if err != nil {
+		r.c.Logger.Info().Msgf("block confirmations is not set. set default:%d", DefaultBlockConfirmations.Int64())
+		blockConfirmations = DefaultBlockConfirmations
+	}
+	blockRange, err := util.StringToBig(chainCfg.BlockRange, 10)
+	if err != nil || blockRange.Sign() <= 0 {
+		r.c.Logger.Info().Msgf("block range is not set. set default:%d", DefaultBlockRange.Int64())
+		blockRange = DefaultBlockRange
+	}
+
+	bs, err := blockstore.NewBlockstore(cfg.BlockStorePath, chainCfg.Name)
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msg("cannot initialize block store")
+	}
+	latestBlock, err := r.c.EvmClient.LatestBlockNumber()
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msg("cannot get latest block through evmclient.")
+	}
+	startBlock, source, err := loadStartBlock(nil, cfg.IsLoaded, chainCfg, bs, latestBlock)
+	if err != nil {
+		r.c.Logger.Panic().Err(err).Msg("cannot decide start block")
+	}
+	r.c.Logger.Info().Msgf("token returns to %s will be paid in BERS. start block : %d (from %s)", address.Hex(), startBlock.Uint64(), source)
+
+	r.reverse = &reverseWatcher{
+		c:                  r.c,
+		token:              r.erc20Contract,
+		store:              r.store,
+		blockStore:         bs,
+		address:            address,
+		confirmation:       confirmation,
+		blockConfirmations: blockConfirmations,
+		blockRange:         blockRange,
+		currentBlock:       startBlock,
+	}
+}
+
+// watchReverse는 주기적으로 확정된 블록까지 토큰 반환을 탐색합니다.
+// 탐색에 실패하면 로그를 남기고 다음 주기에 같은 블록부터 다시 탐색합니다.
+func (r *ReceiverChain) watchReverse() {
+	for {
+		err := r.reverse.poll(r.stop)
+		if err != nil {
+			r.c.Logger.Error().Err(err).Msgf("cannot detect token returns. retry from block %s", r.reverse.currentBlock.String())
+		}
+
+		select {
+		case <-time.After(BlockRetryInterval):
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// poll은 currentBlock부터 확정된 최신 블록까지 blockRange 만큼씩 토큰 반환을 탐색하여 deposit queue에 저장합니다.
+func (w *reverseWatcher) poll(stop <-chan struct{}) error {
+	latestBlock, err := w.c.EvmClient.LatestBlockNumber()
+	if err != nil {
+		return fmt.Errorf("cannot get latest block: %w", err)
+	}
+	confirmedBlock, err := confirmedBlockAt(w.c, w.confirmation, w.blockConfirmations, latestBlock)
+	if err != nil {
+		return err
+	}
+
+	for w.currentBlock.Cmp(confirmedBlock) <= 0 {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		endBlock := new(big.Int).Add(w.currentBlock, w.blockRange)
+		endBlock.Sub(endBlock, big.NewInt(1))
+		if endBlock.Cmp(confirmedBlock) == 1 {
+			endBlock.Set(confirmedBlock)
+		}
+
+		logs, err := w.c.EvmClient.FetchIndexedEventLogs(context.Background(), *w.token.ContractAddress(), message.Transfer,
+			[][]common.Hash{nil, {common.BytesToHash(w.address.Bytes())}}, w.currentBlock, endBlock)
+		if err != nil {
+			return fmt.Errorf("unable to filter transfer logs. from:%s, to:%s, err:%w", w.currentBlock.String(), endBlock.String(), err)
+		}
+		msgs, holds, err := reverseMessages(logs, w.token.ParseTransferValue)
+		if err != nil {
+			return err
+		}
+		for i := range msgs {
+			tx, _, err := w.c.EvmClient.GetTransactionByHash(common.HexToHash(msgs[i].SenderTxHash))
+			if err != nil {
+				return fmt.Errorf("cannot get token return transaction. hash:%s, err:%w", msgs[i].SenderTxHash, err)
+			}
+			recipient, err := reverseRecipient(w.token, tx)
+			if err != nil {
+				// 지급받을 주소를 알 수 없는 반환은 자동으로 지급하지 않는다.
+				if _, ok := holds[msgs[i].SenderTxHash]; !ok {
+					holds[msgs[i].SenderTxHash] = fmt.Sprintf("%s. %s", ReasonNoReverseRecipient, err.Error())
+				}
+				continue
+			}
+			msgs[i].Receiver = recipient
+		}
+
+		err = w.enqueue(msgs, holds)
+		if err != nil {
+			return err
+		}
+		w.checkpoint(endBlock)
+		w.currentBlock = endBlock.Add(endBlock, big.NewInt(1))
+	}
+	return nil
+}
+
+// enqueue는 감지된 토큰 반환을 deposit queue에 reverse 방향으로 저장합니다. holds에 포함된 반환은 held 상태로 저장합니다.
+func (w *reverseWatcher) enqueue(msgs []message.DepositMessage, holds map[string]string) error {
+	for _, m := range msgs {
+		arg := newEnqueueDepositParams(m)
+		arg.Direction = store.DirectionReverse
+
+		if reason, ok := holds[m.SenderTxHash]; ok {
+			err := w.store.EnqueueHeldDepositTx(context.Background(), arg, reason)
+			if err != nil {
+				return fmt.Errorf("cannot enqueue token return. hash:%s, err:%w", m.SenderTxHash, err)
+			}
+			w.c.Logger.Warn().Msgf("%s. hold token return for manual review. hash:%s", reason, m.SenderTxHash)
+			continue
+		}
+
+		_, err := w.store.EnqueueDeposit(context.Background(), arg)
+		if err != nil {
+			return fmt.Errorf("cannot enqueue token return. hash:%s, err:%w", m.SenderTxHash, err)
+		}
+		w.c.Logger.Info().Msgf("enqueued token return. block:%d sender:%s, value:%s", m.BlockNumber, m.Sender.Hex(), m.Amount.String())
+	}
+	return nil
+}
+
+// checkpoint는 탐색을 마친 블록 번호를 blockstore에 저장합니다.
+func (w *reverseWatcher) checkpoint(block *big.Int) {
+	err := w.blockStore.StoreBlock(block)
+	if err != nil {
+		w.c.Logger.Error().Err(err).Msg("Failed to write checkpoint to blockstore")
+	}
+}
+
+// reverseMessages는 Transfer 이벤트 로그를 토큰 반환 메시지로 변환합니다.
+// 한 트랜잭션의 반환은 하나의 메시지로 합치며, 여러 주소가 보낸 토큰이 섞여 있다면 holds에 보류 사유를 기록합니다.
+// zero address가 보낸 로그는 mint 이므로 무시합니다. 메시지의 수신자는 calldata에서 읽기 전까지 zero address입니다.
+func reverseMessages(logs []types.Log, parseValue func(types.Log) (*big.Int, error)) ([]message.DepositMessage, map[string]string, error) {
+	msgs := []message.DepositMessage{}
+	holds := make(map[string]string)
+	index := make(map[common.Hash]int)
+	for _, l := range logs {
+		if len(l.Topics) != 3 {
+			continue
+		}
+		from := common.BytesToAddress(l.Topics[1].Bytes())
+		if from == (common.Address{}) {
+			continue
+		}
+		value, err := parseValue(l)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse transfer value. hash:%s, err:%w", l.TxHash.Hex(), err)
+		}
+
+		if i, ok := index[l.TxHash]; ok {
+			msgs[i].Amount.Add(msgs[i].Amount, value)
+			if msgs[i].Sender != from {
+				holds[l.TxHash.Hex()] = ReasonMultipleReverseSenders
+			}
+			continue
+		}
+		index[l.TxHash] = len(msgs)
+		msgs = append(msgs, message.NewDepositMessage(l.BlockNumber, l.BlockHash, uint64(l.Index), from, common.Address{}, value, l.TxHash.Hex()))
+	}
+	return msgs, holds, nil
+}
+
+// reverseRecipient는 토큰 반환 트랜잭션의 calldata에서 BERS를 지급받을 Berith 주소를 읽습니다.
+// 트랜잭션은 토큰 컨트랙트를 직접 호출해야 하며, 호출한 함수의 인자 뒤에 Berith 주소를 32 bytes로 덧붙여야 합니다.
+func reverseRecipient(token *contract.ERC20Contract, tx *types.Transaction) (common.Address, error) {
+	if tx.To() == nil || *tx.To() != *token.ContractAddress() {
+		return common.Address{}, fmt.Errorf("transaction does not call token contract")
+	}
+	input := tx.Data()
+	if len(input) < 4 {
+		return common.Address{}, fmt.Errorf("invalid calldata")
+	}
+	method, err := token.ABI.MethodById(input[:4])
+	if err != nil {
+		return common.Address{}, fmt.Errorf("unknown token method")
+	}
+
+	args := input[4:]
+	offset := len(method.Inputs) * common.HashLength
+	if len(args) != offset+common.HashLength {
+		return common.Address{}, fmt.Errorf("calldata of %s has no recipient", method.Name)
+	}
+	word := args[offset:]
+	recipient := common.BytesToAddress(word)
+	if common.BytesToHash(recipient.Bytes()) != common.BytesToHash(word) || recipient == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("invalid recipient %s", hexutil.Encode(word))
+	}
+	return recipient, nil
+}
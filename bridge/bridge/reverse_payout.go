@@ -0,0 +1,423 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"berith-swap/bridge/transaction"
+	"berith-swap/bridge/util"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// reversePayer는 deposit queue에 reverse 방향으로 저장된 토큰 반환에 대해 Berith chain의 owner 계정에서 BERS를 지급합니다.
+// 지급 한도와 운영자 승인, swap history는 Klaytn 토큰 지급과 같은 테이블과 상태를 사용합니다.
+type reversePayer struct {
+	c          *chain.Chain
+	store      *store.Store
+	transactor transaction.Transactor
+	conversion *conversion // 반환된 토큰 양을 지급할 BERS 양으로 환산
+	limits     *swapLimits // 반환된 토큰 양으로 환산한 지급 한도
+	screener   *screener   // Klaytn chain config의 denylist와 allowlist
+	retry      retryPolicy // 전송하지 못한 BERS 지급의 재시도 정책
+
+	approvalThreshold *big.Int // 반환된 토큰 양으로 환산한 운영자 승인 기준
+}
+
+// setReversePayer는 Klaytn chain에 reverseAddress가 설정되어 있다면 토큰 반환에 BERS를 지급할 reversePayer를 설정합니다.
+// 환율은 Klaytn chain config의 환율을 역으로 사용하고, 지급 한도와 승인 기준, 재시도 정책은 Berith chain config의 설정을 사용합니다.
+// BERS 양으로 설정된 지급 한도와 승인 기준은 Klaytn chain config의 환율로 토큰 양으로 환산하여 반환된 토큰 양과 비교합니다.
+// 주소 screening은 Klaytn 토큰 지급과 같은 denylist와 allowlist를 사용합니다.
+func (s *SenderChain) setReversePayer(cfg *config.Config, chainCfg *config.RawChainConfig) {
+	if len(cfg.ChainConfig) <= ReceiverIdx || cfg.ChainConfig[ReceiverIdx].ReverseAddress == "" {
+		return
+	}
+
+	t, err := contract.InitializeTransactor(contract.BerithGasPrice, transaction.NewTransaction, s.c.EvmClient)
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msg("cannot init transactor")
+	}
+	conversion, err := newConversion(cfg.ChainConfig[ReceiverIdx])
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msg("invalid conversion config")
+	}
+	limits, err := newSwapLimits(chainCfg)
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msg("invalid swap limit config")
+	}
+	threshold, err := parseLimit(chainCfg.ApprovalThreshold)
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msgf("invalid approvalThreshold:%s", chainCfg.ApprovalThreshold)
+	}
+	screener, err := newScreener(cfg.ChainConfig[ReceiverIdx])
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msg("invalid screening config")
+	}
+
+	s.reverse = &reversePayer{
+		c:                 s.c,
+		store:             s.store,
+		transactor:        t,
+		conversion:        conversion.inverse(),
+		limits:            limits.convert(conversion),
+		approvalThreshold: conversion.convertLimit(threshold),
+		screener:          screener,
+		retry:             newRetryPolicy(chainCfg, &s.c.Logger),
+	}
+}
+
+// watchReverse는 submitted 상태로 남은 BERS 지급을 먼저 정리한 뒤 주기적으로 토큰 반환에 BERS를 지급합니다.
+// 지급에 실패하면 로그를 남기고 다음 주기에 다시 시도합니다.
+func (s *SenderChain) watchReverse() {
+	ticker := time.NewTicker(QueuePollInterval)
+	defer ticker.Stop()
+
+	startup := true
+	for {
+		err := s.reverse.reconcile(startup)
+		if err != nil {
+			s.c.Logger.Error().Err(err).Msg("cannot reconcile submitted BERS payouts. retry next poll")
+		} else {
+			startup = false
+			if err := s.reverse.processQueue(); err != nil {
+				s.c.Logger.Error().Err(err).Msg("cannot pay out token returns. retry next poll")
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// processQueue는 아직 지급되지 않은 토큰 반환을 감지된 순서대로 지급합니다.
+// 주소 screening에 걸린 반환과 운영자가 승인하지 않은 반환 중 지급 한도를 넘는 반환은 held 상태로,
+// 승인 기준 이상인 반환은 pending_approval 상태로 보류하고,
+// owner 계정의 BERS 잔액이 부족하다면 남은 반환은 queue에 남겨둡니다.
+// 전송하지 못한 반환은 재시도 정책에 따라 backoff 이후 다시 지급하므로 뒤의 반환을 막지 않습니다.
+func (p *reversePayer) processQueue() error {
+	p.screener.reload(&p.c.Logger)
+	rows, err := p.store.ListPendingDeposits(context.Background(), mariadb.ListPendingDepositsParams{
+		Direction: store.DirectionReverse,
+		Limit:     QueueBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get token returns from deposit queue. err:%w", err)
+	}
+
+	var usage *limitUsage
+	for _, row := range rows {
+		m, err := depositFromQueue(row)
+		if err != nil {
+			p.c.Logger.Warn().Err(err).Msg("Invalid token return message.")
+			if err := p.updateStatus(row.SenderTxHash, store.DepositInvalid); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.Receiver == (common.Address{}) {
+			// 운영자가 승인한 반환이라도 지급받을 Berith 주소를 알 수 없다면 지급하지 않는다.
+			if err := p.hold(m, ReasonNoReverseRecipient); err != nil {
+				return err
+			}
+			continue
+		}
+		if valErr := util.ValidateStruct(m); valErr != nil {
+			p.c.Logger.Warn().Msgf("Invalid token return message. %s", valErr.Error())
+			if err := p.updateStatus(row.SenderTxHash, store.DepositInvalid); err != nil {
+				return err
+			}
+			continue
+		}
+
+		history, err := p.store.GetBersSwapHistory(context.Background(), m.SenderTxHash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("cannot get swap history. hash:%s, err:%w", m.SenderTxHash, err)
+		}
+		if history.SenderTxHash != "" {
+			p.c.Logger.Warn().Msgf("swap already excecuted, ignore token return. sender tx: %s", m.SenderTxHash)
+			if err := p.updateStatus(m.SenderTxHash, store.DepositConfirmed); err != nil {
+				return err
+			}
+			continue
+		}
+
+		amount := p.conversion.convert(m.Amount)
+		if amount.Sign() <= 0 {
+			p.c.Logger.Warn().Msgf("token return is too small to pay out. sender tx: %s, value:%s", m.SenderTxHash, m.Amount.String())
+			if err := p.updateStatus(m.SenderTxHash, store.DepositInvalid); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// 승인한 뒤에 denylist에 추가된 주소에도 지급하지 않도록 운영자가 승인한 반환도 항상 검사한다.
+		if reason := p.screener.screen(m); reason != "" {
+			if err := p.hold(m, reason); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !row.ApprovedAt.Valid {
+			if usage == nil {
+				usage, err = loadLimitUsage(p.store, p.limits, store.DirectionReverse, nil)
+				if err != nil {
+					return err
+				}
+			}
+			parked, err := p.requireReview(usage, m)
+			if err != nil {
+				return err
+			}
+			if parked {
+				continue
+			}
+		}
+
+		balance, err := p.c.EvmClient.BalanceAt(context.Background(), p.c.EvmClient.From(), nil)
+		if err != nil {
+			return fmt.Errorf("cannot get owner balance. err:%w", err)
+		}
+		if balance.Cmp(amount) < 0 {
+			// 잔액이 채워질 때까지 남은 반환은 detected 상태로 queue에 남겨둔다.
+			p.c.Logger.Warn().Msgf("insufficient owner balance for BERS payout. hash:%s, balance:%s, required:%s", m.SenderTxHash, balance.String(), amount.String())
+			return nil
+		}
+
+		err = p.pay(row, m, amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pay는 토큰 반환 트랜잭션에 지정된 Berith 주소로 BERS를 전송하고 receipt를 기다립니다.
+// 트랜잭션을 전송하기 전에 서명된 tx hash와 nonce, 지급액을 deposit queue에 submitted 상태로 저장합니다.
+func (p *reversePayer) pay(row mariadb.BersDepositQueue, m message.DepositMessage, amount *big.Int) error {
+	q := p.quote(m, amount)
+	opts := transaction.TransactOptions{
+		GasLimit: p.c.GasLimit.Uint64(),
+		Value:    amount,
+		OnSigned: func(hash common.Hash, nonce uint64) error {
+			// detected 상태가 아닌 반환은 이미 지급 중이거나 보류되었으므로 에러를 반환하여 트랜잭션을 전송하지 않는다.
+			return p.store.MarkDepositsSubmittedTx(context.Background(), q.submittedParams(m.SenderTxHash, hash, nonce))
+		},
+	}
+	txHash, err := p.transactor.Submit(&m.Receiver, nil, opts)
+	if err != nil {
+		return p.handleSubmitError(m, err)
+	}
+	p.c.Logger.Info().Msgf("submitted BERS payout. sender tx:%s, receiver:%s, value:%s, Tx Hash:%s", m.SenderTxHash, m.Receiver.Hex(), amount.String(), txHash.Hex())
+
+	rec, err := p.c.EvmClient.WaitAndReturnTxReceipt(*txHash)
+	if err != nil && (rec == nil || rec.Status != types.ReceiptStatusFailed) {
+		p.c.Logger.Warn().Err(err).Msgf("BERS payout is not confirmed yet. reconcile later. hash:%s, tx:%s", m.SenderTxHash, txHash.Hex())
+		return nil
+	}
+	return p.resolve(row, m, q, txHash.Hex(), rec)
+}
+
+// handleSubmitError는 BERS 지급 트랜잭션을 전송하지 못한 반환을 재시도 대상으로 기록합니다.
+// 서명된 트랜잭션이 submitted 상태로 저장된 뒤 전송에 실패했다면 노드에 전달되었을 수 있으므로 reconcile에서 확인합니다.
+func (p *reversePayer) handleSubmitError(m message.DepositMessage, cause error) error {
+	row, err := p.store.GetDeposit(context.Background(), m.SenderTxHash)
+	if err != nil {
+		return fmt.Errorf("cannot get token return from deposit queue. hash:%s, err:%w", m.SenderTxHash, err)
+	}
+	if row.Status == store.DepositSubmitted {
+		p.c.Logger.Warn().Err(cause).Msgf("signed BERS payout may have been broadcast. reconcile later. hash:%s, tx:%s", m.SenderTxHash, row.ReceiverTxHash.String)
+		return nil
+	}
+	if errors.Is(cause, store.ErrDepositNotDetected) {
+		p.c.Logger.Warn().Err(cause).Msgf("token return is no longer payable. skip BERS payout. hash:%s, status:%s", m.SenderTxHash, row.Status)
+		return nil
+	}
+	return p.retry.retryDeposit(p.store, &p.c.Logger, m.SenderTxHash, row.RetryCount, cause)
+}
+
+// reconcile은 submitted 상태로 남은 BERS 지급을 체인의 상태와 비교하여 정리합니다.
+// 트랜잭션이 체인에 존재하지 않는데 계정의 nonce가 이미 해당 nonce를 지났다면 held 상태로 보류하고,
+// 지나지 않았고 막 시작되었다면 다시 지급할 수 있도록 detected 상태로 되돌립니다.
+// DroppedTransferTimeout이 지나도록 찾을 수 없는 트랜잭션은 mempool에서 사라진 것으로 보고 계정의 nonce를 다시 조회한 뒤 다시 지급합니다.
+func (p *reversePayer) reconcile(startup bool) error {
+	rows, err := p.store.ListDepositsByStatus(context.Background(), mariadb.ListDepositsByStatusParams{
+		Direction: store.DirectionReverse,
+		Status:    store.DepositSubmitted,
+		Limit:     ReconcileBatchSize,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get submitted token returns. err:%w", err)
+	}
+
+	for _, row := range rows {
+		m, err := depositFromQueue(row)
+		if err != nil {
+			return err
+		}
+		q, ok := submittedQuote(row)
+		if !ok {
+			q = p.quote(m, p.conversion.convert(m.Amount))
+		}
+		hash := common.HexToHash(row.ReceiverTxHash.String)
+		rec, err := p.c.EvmClient.TransactionReceipt(context.Background(), hash)
+		if err == nil {
+			if err := p.resolve(row, m, q, hash.Hex(), rec); err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("cannot get receipt of BERS payout. tx:%s, err:%w", hash.Hex(), err)
+		}
+
+		_, _, err = p.c.EvmClient.GetTransactionByHash(hash)
+		if err == nil {
+			p.c.Logger.Info().Msgf("BERS payout is pending. tx:%s", hash.Hex())
+			continue
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("cannot get BERS payout transaction. tx:%s, err:%w", hash.Hex(), err)
+		}
+
+		nonce, err := p.c.EvmClient.NonceAt(context.Background(), p.c.EvmClient.From(), nil)
+		if err != nil {
+			return fmt.Errorf("cannot get account nonce. err:%w", err)
+		}
+		switch {
+		case uint64(row.ReceiverNonce.Int64) < nonce:
+			// 노드가 트랜잭션을 찾지 못할 뿐 지급되었을 수 있으므로 다시 지급하지 않는다.
+			reason := fmt.Sprintf("%s. tx:%s, nonce:%d, account nonce:%d", ReasonTransferNotFound, hash.Hex(), row.ReceiverNonce.Int64, nonce)
+			err := p.store.HoldSubmittedDepositTx(context.Background(), mariadb.CreateSwapReviewParams{
+				SenderTxHash: row.SenderTxHash,
+				Reason:       reason,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot hold token return. hash:%s, err:%w", row.SenderTxHash, err)
+			}
+			p.c.Logger.Warn().Msgf("%s. hold token return for manual review. hash:%s", reason, row.SenderTxHash)
+			continue
+		case startup:
+			p.c.Logger.Warn().Msgf("BERS payout was not broadcast. pay again. tx:%s, nonce:%d", hash.Hex(), row.ReceiverNonce.Int64)
+		case row.UpdatedAt.Valid && time.Since(row.UpdatedAt.Time) >= DroppedTransferTimeout:
+			p.c.Logger.Warn().Msgf("BERS payout was dropped from mempool. pay again. tx:%s, nonce:%d, submitted at:%s", hash.Hex(), row.ReceiverNonce.Int64, row.UpdatedAt.Time)
+			p.c.EvmClient.ResetNonce()
+		default:
+			p.c.Logger.Warn().Msgf("BERS payout not found. tx:%s, nonce:%d", hash.Hex(), row.ReceiverNonce.Int64)
+			continue
+		}
+		if err := p.store.ResetDeposit(context.Background(), row.SenderTxHash); err != nil {
+			return fmt.Errorf("cannot reset token return. hash:%s, err:%w", row.SenderTxHash, err)
+		}
+	}
+	return nil
+}
+
+// resolve는 BERS 지급 트랜잭션의 receipt에 따라 swap history를 저장하거나 반환을 dead letter 테이블로 옮깁니다.
+// native coin 전송이 revert 되는 것은 수신 주소의 문제이므로 재시도하지 않고 운영자가 확인하도록 합니다.
+// swap history에는 트랜잭션을 전송할 때 저장한 지급액 q를 기록합니다.
+func (p *reversePayer) resolve(row mariadb.BersDepositQueue, m message.DepositMessage, q quote, txHash string, rec *types.Receipt) error {
+	if rec.Status != types.ReceiptStatusSuccessful {
+		err := p.store.DeadLetterDepositTx(context.Background(), mariadb.RecordDepositErrorParams{
+			RetryCount:   row.RetryCount + 1,
+			LastError:    sql.NullString{String: fmt.Sprintf("BERS payout reverted. tx:%s", txHash), Valid: true},
+			SenderTxHash: m.SenderTxHash,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot move token return to dead letter. hash:%s, err:%w", m.SenderTxHash, err)
+		}
+		p.c.Logger.Error().Msgf("BERS payout reverted. moved token return to dead letter. hash:%s, tx:%s", m.SenderTxHash, txHash)
+		return nil
+	}
+
+	err := p.store.CompleteDepositTx(context.Background(), mariadb.CreateBersSwapHistoryParams{
+		SenderTxHash:   m.SenderTxHash,
+		ReceiverTxHash: txHash,
+		BerithAddress:  m.Receiver.Hex(),
+		Amount:         legacyAmount(q.deposit),
+		DepositAmount:  q.deposit.String(),
+		GrossAmount:    q.gross.String(),
+		FeeAmount:      q.fee.String(),
+		PayoutAmount:   q.amount.String(),
+		Direction:      store.DirectionReverse,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot store swap history. hash:%s, err:%w", m.SenderTxHash, err)
+	}
+	p.c.Logger.Info().Msgf("paid BERS for token return. receiver:%s, sender tx:%s, Tx Hash:%s", m.Receiver.Hex(), m.SenderTxHash, txHash)
+	return nil
+}
+
+// quote는 반환된 토큰 양과 지급할 BERS 양으로 quote를 생성합니다. 토큰 반환에는 수수료를 부과하지 않습니다.
+func (p *reversePayer) quote(m message.DepositMessage, amount *big.Int) quote {
+	return quote{
+		deposit: m.Amount,
+		gross:   amount,
+		fee:     new(big.Int),
+		amount:  amount,
+	}
+}
+
+// requireReview는 운영자가 승인하지 않은 반환이 승인 기준 이상이라면 pending_approval 상태로,
+// 지급 한도를 넘는다면 held 상태로 보류하고 true를 반환합니다.
+func (p *reversePayer) requireReview(u *limitUsage, m message.DepositMessage) (bool, error) {
+	if p.approvalThreshold != nil && m.Amount.Cmp(p.approvalThreshold) >= 0 {
+		reason := fmt.Sprintf("token return amount requires approval. amount:%s, threshold:%s", m.Amount.String(), p.approvalThreshold.String())
+		err := p.store.RequestDepositApproval(context.Background(), mariadb.RequestDepositApprovalParams{
+			Reason:       sql.NullString{String: reason, Valid: true},
+			SenderTxHash: m.SenderTxHash,
+		})
+		if err != nil {
+			return false, fmt.Errorf("cannot request token return approval. hash:%s, err:%w", m.SenderTxHash, err)
+		}
+		p.c.Logger.Warn().Msgf("%s. wait for operator approval. hash:%s", reason, m.SenderTxHash)
+		return true, nil
+	}
+
+	reason, err := u.check(m)
+	if err != nil {
+		return false, err
+	}
+	if reason != "" {
+		return true, p.hold(m, reason)
+	}
+	return false, nil
+}
+
+// hold는 지급 한도를 넘는 토큰 반환을 수동 검토 대상으로 기록하고 held 상태로 보류합니다.
+func (p *reversePayer) hold(m message.DepositMessage, reason string) error {
+	err := p.store.HoldDepositTx(context.Background(), mariadb.CreateSwapReviewParams{
+		SenderTxHash: m.SenderTxHash,
+		Reason:       reason,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot hold token return. hash:%s, err:%w", m.SenderTxHash, err)
+	}
+	p.c.Logger.Warn().Msgf("%s. hold token return for manual review. hash:%s", reason, m.SenderTxHash)
+	return nil
+}
+
+func (p *reversePayer) updateStatus(senderTxHash, status string) error {
+	err := p.store.UpdateDepositStatus(context.Background(), mariadb.UpdateDepositStatusParams{
+		Status:       status,
+		SenderTxHash: senderTxHash,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update token return status. hash:%s, status:%s, err:%w", senderTxHash, status, err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package bridge
+
+import (
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"database/sql"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseMessages(t *testing.T) {
+	logger := zerolog.Nop()
+	token := contract.NewERC20Contract(nil, common.HexToAddress("0x01"), nil, &logger)
+
+	var (
+		user    = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		other   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		reverse = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		tx1     = common.HexToHash("0x01")
+		tx2     = common.HexToHash("0x02")
+		tx3     = common.HexToHash("0x03")
+	)
+	transfer := func(tx common.Hash, index uint, from common.Address, value int64) types.Log {
+		return types.Log{
+			Topics: []common.Hash{
+				message.Transfer.GetTopic(),
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(reverse.Bytes()),
+			},
+			Data:        common.LeftPadBytes(big.NewInt(value).Bytes(), 32),
+			BlockNumber: 10,
+			TxHash:      tx,
+			Index:       index,
+		}
+	}
+
+	logs := []types.Log{
+		transfer(tx1, 0, user, 100),
+		transfer(tx1, 1, user, 50),
+		transfer(tx2, 2, common.Address{}, 70), // mint
+		transfer(tx3, 3, user, 30),
+		transfer(tx3, 4, other, 20),
+	}
+	msgs, holds, err := reverseMessages(logs, token.ParseTransferValue)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+
+	require.Equal(t, tx1.Hex(), msgs[0].SenderTxHash)
+	require.Equal(t, user, msgs[0].Sender)
+	require.Equal(t, common.Address{}, msgs[0].Receiver)
+	require.Equal(t, int64(150), msgs[0].Amount.Int64())
+	require.Equal(t, uint64(0), msgs[0].DepositNonce)
+
+	require.Equal(t, tx3.Hex(), msgs[1].SenderTxHash)
+	require.Equal(t, int64(50), msgs[1].Amount.Int64())
+	require.Equal(t, map[string]string{tx3.Hex(): ReasonMultipleReverseSenders}, holds)
+}
+
+// 토큰 컨트랙트를 직접 호출한 calldata의 인자 뒤에 덧붙인 주소를 Berith 수신자로 읽는가?
+func TestReverseRecipient(t *testing.T) {
+	logger := zerolog.Nop()
+	tokenAddress := common.HexToAddress("0x01")
+	token := contract.NewERC20Contract(nil, tokenAddress, nil, &logger)
+
+	var (
+		reverse   = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		recipient = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	)
+	transfer, err := token.PackMethod("transfer", reverse, big.NewInt(100))
+	require.NoError(t, err)
+	withRecipient := append(append([]byte{}, transfer...), common.LeftPadBytes(recipient.Bytes(), 32)...)
+
+	tx := func(to *common.Address, data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: to, Data: data})
+	}
+
+	got, err := reverseRecipient(token, tx(&tokenAddress, withRecipient))
+	require.NoError(t, err)
+	require.Equal(t, recipient, got)
+
+	other := common.HexToAddress("0x02")
+	var testCases = []struct {
+		name string
+		tx   *types.Transaction
+	}{
+		{name: "no recipient", tx: tx(&tokenAddress, transfer)},
+		{name: "other contract", tx: tx(&other, withRecipient)},
+		{name: "contract creation", tx: tx(nil, withRecipient)},
+		{name: "unknown method", tx: tx(&tokenAddress, append([]byte{0xde, 0xad, 0xbe, 0xef}, withRecipient[4:]...))},
+		{name: "zero recipient", tx: tx(&tokenAddress, append(append([]byte{}, transfer...), make([]byte, 32)...))},
+		{name: "dirty padding", tx: tx(&tokenAddress, append(append([]byte{}, transfer...), append(append([]byte{1}, make([]byte, 11)...), recipient.Bytes()...)...))},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := reverseRecipient(token, tc.tx)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReverseRequireReview(t *testing.T) {
+	st, db := newTestStore(t)
+	// 1 BERS = 2 토큰
+	conversion, err := newConversion(&config.RawChainConfig{RateNumerator: "2"})
+	require.NoError(t, err)
+	limits, err := newSwapLimits(&config.RawChainConfig{MinSwapAmount: "10", MaxSwapAmount: "100"})
+	require.NoError(t, err)
+	p := &reversePayer{
+		c:                 &chain.Chain{Logger: zerolog.Nop()},
+		store:             st,
+		limits:            limits.convert(conversion),
+		approvalThreshold: conversion.convertLimit(big.NewInt(50)),
+	}
+	require.Equal(t, big.NewInt(20), p.limits.min)
+	require.Equal(t, big.NewInt(200), p.limits.max)
+	require.Equal(t, big.NewInt(100), p.approvalThreshold)
+
+	usage, err := loadLimitUsage(st, p.limits, store.DirectionReverse, nil)
+	require.NoError(t, err)
+	tokenReturn := func(amount int64) message.DepositMessage {
+		return message.NewDepositMessage(1, common.Hash{}, uint64(amount), common.HexToAddress("0x1"), common.HexToAddress("0x2"), big.NewInt(amount), common.BigToHash(big.NewInt(amount)).Hex())
+	}
+
+	// 한도와 승인 기준은 BERS 양이 아닌 반환된 토큰 양과 비교한다.
+	parked, err := p.requireReview(usage, tokenReturn(90))
+	require.NoError(t, err)
+	require.False(t, parked)
+
+	below := tokenReturn(15)
+	parked, err = p.requireReview(usage, below)
+	require.NoError(t, err)
+	require.True(t, parked)
+	held := db.executed("HoldDeposit")
+	require.Len(t, held, 1)
+	require.Equal(t, below.SenderTxHash, held[0][1])
+
+	large := tokenReturn(100)
+	parked, err = p.requireReview(usage, large)
+	require.NoError(t, err)
+	require.True(t, parked)
+	approval := db.executed("RequestDepositApproval")
+	require.Len(t, approval, 1)
+	require.Equal(t, large.SenderTxHash, approval[0][1])
+	require.Len(t, db.executed("HoldDeposit"), 1)
+}
+
+// 시작 시점에 찾을 수 없는 BERS 지급은 계정의 nonce가 지나지 않았을 때만 다시 지급하는가?
+func TestReverseReconcileNotFound(t *testing.T) {
+	client := newTestRPCClient(t, func(method string, params []json.RawMessage) interface{} {
+		if method == "eth_getTransactionCount" {
+			return hexutil.Uint64(5)
+		}
+		// receipt와 트랜잭션을 찾을 수 없다.
+		return nil
+	})
+
+	row := func(nonce int64) mariadb.BersDepositQueue {
+		return mariadb.BersDepositQueue{
+			SenderTxHash:    common.BigToHash(big.NewInt(nonce)).Hex(),
+			SenderAddress:   common.HexToAddress("0x1").Hex(),
+			ReceiverAddress: common.HexToAddress("0x2").Hex(),
+			Amount:          "1",
+			Status:          store.DepositSubmitted,
+			Direction:       store.DirectionReverse,
+			ReceiverTxHash:  sql.NullString{String: common.BigToHash(big.NewInt(100 + nonce)).Hex(), Valid: true},
+			ReceiverNonce:   sql.NullInt64{Int64: nonce, Valid: true},
+		}
+	}
+	unsent, used := row(5), row(3)
+	st, db := newTestStore(t, unsent, used)
+	conversion, err := newConversion(&config.RawChainConfig{})
+	require.NoError(t, err)
+	p := &reversePayer{
+		c:          &chain.Chain{EvmClient: client, Logger: zerolog.Nop()},
+		store:      st,
+		conversion: conversion.inverse(),
+	}
+	require.NoError(t, p.reconcile(true))
+
+	// 계정의 nonce가 지나지 않았다면 다음 지급이 같은 nonce를 사용하므로 다시 지급한다.
+	reset := db.executed("ResetDeposit")
+	require.Len(t, reset, 1)
+	require.Equal(t, unsent.SenderTxHash, reset[0][0])
+
+	// 계정의 nonce가 지났다면 지급되었을 수 있으므로 held 상태로 보류한다.
+	held := db.executed("HoldSubmittedDeposit")
+	require.Len(t, held, 1)
+	require.Contains(t, held[0][0], ReasonTransferNotFound)
+	require.Equal(t, used.SenderTxHash, held[0][1])
+}
@@ -0,0 +1,167 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+)
+
+// 주소 screening에 걸린 deposit의 보류 사유 코드
+const (
+	ReasonDenylistedSender       = "denylisted_sender"
+	ReasonDenylistedReceiver     = "denylisted_receiver"
+	ReasonNotAllowlistedSender   = "not_allowlisted_sender"
+	ReasonNotAllowlistedReceiver = "not_allowlisted_receiver"
+)
+
+// addressList는 파일에서 읽은 주소 목록입니다. 파일이 변경되면 다시 읽습니다.
+type addressList struct {
+	path      string
+	modTime   time.Time
+	addresses map[common.Address]struct{}
+}
+
+// load는 파일이 마지막으로 읽은 뒤 변경되었다면 주소 목록을 다시 읽고 true를 반환합니다.
+// 파일을 읽지 못하면 이전 목록을 유지합니다.
+func (l *addressList) load() (bool, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, err
+	}
+	if l.addresses != nil && info.ModTime().Equal(l.modTime) {
+		return false, nil
+	}
+
+	addresses, err := readAddressList(l.path)
+	if err != nil {
+		return false, err
+	}
+	l.addresses = addresses
+	l.modTime = info.ModTime()
+	return true, nil
+}
+
+func (l *addressList) contains(addr common.Address) bool {
+	_, ok := l.addresses[addr]
+	return ok
+}
+
+// readAddressList는 한 줄에 하나의 주소가 적힌 파일을 읽습니다. 빈 줄과 # 이후의 주석은 무시합니다.
+func readAddressList(path string) (map[common.Address]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	addresses := make(map[common.Address]struct{})
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if !common.IsHexAddress(text) {
+			return nil, fmt.Errorf("invalid address. file:%s, line:%d, address:%s", path, line, text)
+		}
+		addresses[common.HexToAddress(text)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// screener는 deposit의 Berith sender 주소와 Klaytn receiver 주소를 denylist와 allowlist로 검사합니다.
+// denylist에 포함된 주소와, allowlist가 설정되어 있다면 allowlist에 포함되지 않은 주소의 deposit은 지급하지 않습니다.
+type screener struct {
+	deny  *addressList
+	allow *addressList
+}
+
+// newScreener는 config에 설정된 denylist와 allowlist 파일을 읽어 screener를 생성합니다.
+func newScreener(chainCfg *config.RawChainConfig) (*screener, error) {
+	s := &screener{}
+	if chainCfg.DenylistPath != "" {
+		s.deny = &addressList{path: chainCfg.DenylistPath}
+		if _, err := s.deny.load(); err != nil {
+			return nil, fmt.Errorf("cannot load denylist. err:%w", err)
+		}
+	}
+	if chainCfg.AllowlistPath != "" {
+		s.allow = &addressList{path: chainCfg.AllowlistPath}
+		if _, err := s.allow.load(); err != nil {
+			return nil, fmt.Errorf("cannot load allowlist. err:%w", err)
+		}
+	}
+	return s, nil
+}
+
+// screen은 deposit의 주소가 screening에 걸린다면 보류 사유를 반환합니다. 통과한다면 빈 문자열을 반환합니다.
+func (s *screener) screen(m message.DepositMessage) string {
+	switch {
+	case s.deny != nil && s.deny.contains(m.Sender):
+		return fmt.Sprintf("%s. address:%s", ReasonDenylistedSender, m.Sender.Hex())
+	case s.deny != nil && s.deny.contains(m.Receiver):
+		return fmt.Sprintf("%s. address:%s", ReasonDenylistedReceiver, m.Receiver.Hex())
+	case s.allow != nil && !s.allow.contains(m.Sender):
+		return fmt.Sprintf("%s. address:%s", ReasonNotAllowlistedSender, m.Sender.Hex())
+	case s.allow != nil && !s.allow.contains(m.Receiver):
+		return fmt.Sprintf("%s. address:%s", ReasonNotAllowlistedReceiver, m.Receiver.Hex())
+	}
+	return ""
+}
+
+// screenDeposit은 deposit의 주소가 screening에 걸린다면 held 상태로 보류하고 true를 반환합니다.
+// 승인한 뒤에 denylist에 추가된 주소도 지급하지 않도록 운영자가 승인한 deposit도 지급 전에 항상 검사합니다.
+func (r *ReceiverChain) screenDeposit(m message.DepositMessage) (bool, error) {
+	reason := r.screener.screen(m)
+	if reason == "" {
+		return false, nil
+	}
+	return true, r.holdDeposit(m, reason)
+}
+
+// reloadScreening은 denylist와 allowlist 파일이 변경되었다면 다시 읽습니다.
+// 파일을 읽지 못하면 이전 목록으로 screening을 계속합니다.
+func (r *ReceiverChain) reloadScreening() {
+	r.screener.reload(&r.c.Logger)
+}
+
+// reload는 denylist와 allowlist 파일이 변경되었다면 다시 읽습니다.
+func (s *screener) reload(logger *zerolog.Logger) {
+	for name, l := range map[string]*addressList{"denylist": s.deny, "allowlist": s.allow} {
+		if l == nil {
+			continue
+		}
+		reloaded, err := l.load()
+		if err != nil {
+			logger.Error().Err(err).Msgf("cannot reload %s. keep previous list. path:%s", name, l.path)
+			continue
+		}
+		if reloaded {
+			logger.Info().Msgf("reloaded %s. path:%s, addresses:%d", name, l.path, len(l.addresses))
+		}
+	}
+}
+
+// isScreeningReason은 보류 사유가 주소 screening의 사유 코드로 시작하는지 확인합니다.
+func isScreeningReason(reason string) bool {
+	for _, code := range []string{ReasonDenylistedSender, ReasonDenylistedReceiver, ReasonNotAllowlistedSender, ReasonNotAllowlistedReceiver} {
+		if strings.HasPrefix(reason, code) {
+			return true
+		}
+	}
+	return false
+}
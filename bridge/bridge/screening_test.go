@@ -0,0 +1,76 @@
+package bridge
+
+import (
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/message"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreenerScreen(t *testing.T) {
+	var (
+		denied   = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		allowed  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		allowed2 = common.HexToAddress("0x3333333333333333333333333333333333333333")
+		unknown  = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	)
+
+	dir := t.TempDir()
+	denyPath := filepath.Join(dir, "denylist.txt")
+	allowPath := filepath.Join(dir, "allowlist.txt")
+	require.NoError(t, os.WriteFile(denyPath, []byte("# sanctioned\n"+denied.Hex()+"\n\n"), 0644))
+	require.NoError(t, os.WriteFile(allowPath, []byte(allowed.Hex()+" # exchange\n"+strings.ToLower(allowed2.Hex())+"\n"), 0644))
+
+	s, err := newScreener(&config.RawChainConfig{DenylistPath: denyPath, AllowlistPath: allowPath})
+	require.NoError(t, err)
+
+	deposit := func(sender, receiver common.Address) message.DepositMessage {
+		return message.NewDepositMessage(1, common.Hash{}, 1, sender, receiver, big.NewInt(1), "0x")
+	}
+
+	var testCases = []struct {
+		name   string
+		m      message.DepositMessage
+		reason string
+	}{
+		{name: "allowed", m: deposit(allowed, allowed2), reason: ""},
+		{name: "denylisted sender", m: deposit(denied, allowed), reason: ReasonDenylistedSender},
+		{name: "denylisted receiver", m: deposit(allowed, denied), reason: ReasonDenylistedReceiver},
+		{name: "sender not allowlisted", m: deposit(unknown, allowed), reason: ReasonNotAllowlistedSender},
+		{name: "receiver not allowlisted", m: deposit(allowed, unknown), reason: ReasonNotAllowlistedReceiver},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reason := s.screen(tc.m)
+			if tc.reason == "" {
+				require.Empty(t, reason)
+				return
+			}
+			require.True(t, strings.HasPrefix(reason, tc.reason), reason)
+		})
+	}
+
+	// 파일이 변경되면 목록을 다시 읽는다.
+	require.NoError(t, os.WriteFile(denyPath, []byte(allowed.Hex()+"\n"), 0644))
+	require.NoError(t, os.Chtimes(denyPath, time.Now(), time.Now().Add(time.Minute)))
+	reloaded, err := s.deny.load()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Empty(t, s.screen(deposit(allowed2, allowed2)))
+	require.True(t, strings.HasPrefix(s.screen(deposit(allowed, allowed2)), ReasonDenylistedSender))
+
+	// 잘못된 주소가 포함된 파일은 읽지 않고 이전 목록을 유지한다.
+	require.NoError(t, os.WriteFile(denyPath, []byte("not an address\n"), 0644))
+	require.NoError(t, os.Chtimes(denyPath, time.Now(), time.Now().Add(2*time.Minute)))
+	_, err = s.deny.load()
+	require.Error(t, err)
+	require.True(t, s.deny.contains(allowed))
+}
@@ -4,13 +4,17 @@ import (
 	"berith-swap/bridge/blockstore"
 	"berith-swap/bridge/chain"
 	"berith-swap/bridge/config"
+	"berith-swap/bridge/connection"
 	"berith-swap/bridge/contract"
 	"berith-swap/bridge/message"
+	"berith-swap/bridge/store"
 	"berith-swap/bridge/util"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -21,21 +25,32 @@ var (
 	BlockRetryLimit           = 5
 	BlockRetryInterval        = time.Second * 5
 	DefaultBlockConfirmations = big.NewInt(10)
+	DefaultBlockRange         = big.NewInt(1)
+
+	errSenderStopped = errors.New("sender chain stopped")
 )
 
-// SenderChain은 Bridge 컨트랙트를 모니터링하며 Deposit 이벤트가 감지되면 ReceiverChain으로 토큰 전송 메시지를 보냅니다.
+// SenderChain은 Bridge 컨트랙트를 모니터링하며 Deposit 이벤트가 감지되면 deposit queue에 저장합니다.
 type SenderChain struct {
 	c                  *chain.Chain
-	msgChan            chan<- message.DepositMessage
 	blockStore         *blockstore.Blockstore
 	blockConfirmations *big.Int
+	confirmation       string
+	blockRange         *big.Int
 	swapContract       *contract.SwapContract
 	startBlock         *big.Int
+	blockRecords       []blockstore.BlockRecord
+	lastNonce          blockstore.NonceRecord
+	nonceGapRescan     bool
+	verifier           *depositVerifier
+	store              *store.Store
+	refunder           *refunder
+	reverse            *reversePayer // 토큰 반환에 BERS를 지급하지 않는다면 nil
 	stop               chan struct{}
 }
 
 // NewSenderChain는 SenderChain을 생성합니다.
-func NewSenderChain(ch chan<- message.DepositMessage, cfg *config.Config, idx int, bs *blockstore.Blockstore) *SenderChain {
+func NewSenderChain(cfg *config.Config, idx int, bs *blockstore.Blockstore) *SenderChain {
 	chain, err := chain.NewChain(cfg, idx)
 	if err != nil {
 		chain.Logger.Panic().Err(err).Msgf("cannot init chain. idx:%d", idx)
@@ -47,33 +62,68 @@ func NewSenderChain(ch chan<- message.DepositMessage, cfg *config.Config, idx in
 		blockConfirmations = DefaultBlockConfirmations
 	}
 
-	startBlock, err := chain.EvmClient.LatestBlockNumber()
+	confirmation, err := parseConfirmationStrategy(cfg.ChainConfig[idx].ConfirmationStrategy)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("check config.json")
+	}
+	if confirmation == ConfirmBySafe || confirmation == ConfirmByFinalized {
+		if _, err := chain.EvmClient.TaggedBlockNumber(confirmation); err != nil {
+			chain.Logger.Panic().Err(err).Msgf("endpoint dosen't support %s block tag", confirmation)
+		}
+	}
+	chain.Logger.Info().Msgf("confirmation strategy: %s", confirmation)
+
+	blockRange, err := util.StringToBig(cfg.ChainConfig[idx].BlockRange, 10)
+	if err != nil || blockRange.Sign() <= 0 {
+		chain.Logger.Info().Msgf("block range is not set. set default:%d", DefaultBlockRange.Int64())
+		blockRange = DefaultBlockRange
+	}
+
+	latestBlock, err := chain.EvmClient.LatestBlockNumber()
 	if err != nil {
 		chain.Logger.Error().Err(err).Msgf("cannot get latest block through evmclient.")
 		return nil
 	}
 
-	if cfg.IsLoaded {
-		chain.Logger.Info().Msgf("try load latest block from block store isLoaded:%v", cfg.IsLoaded)
-		startBlock, err = bs.TryLoadLatestBlock()
-		if err != nil || startBlock == nil {
-			chain.Logger.Panic().Err(err).Msgf("cannot load latest block from block store. isBlockNumberNil: %v", startBlock == nil)
-		}
-		chain.Logger.Info().Msgf("loaded latest block number form blockstore. path:%s, number:%d", bs.FullPath(), startBlock.Uint64())
+	startBlock, source, err := loadStartBlock(cfg.StartBlock, cfg.IsLoaded, cfg.ChainConfig[idx], bs, latestBlock)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("cannot decide start block")
 	}
+	chain.Logger.Info().Msgf("start block : %d (from %s), latest block : %d", startBlock.Uint64(), source, latestBlock.Uint64())
 
-	chain.Logger.Info().Msgf("Latest block : %d", startBlock.Uint64())
+	records, err := bs.TryLoadBlockRecords()
+	if err != nil {
+		chain.Logger.Error().Err(err).Msg("cannot load block records from block store. reorg detection starts from scratch")
+	}
+
+	lastNonce, err := bs.TryLoadNonce()
+	if err != nil {
+		chain.Logger.Error().Err(err).Msg("cannot load deposit nonce from block store. nonce check starts from scratch")
+	}
+
+	store, err := store.NewStore(cfg.DBSource)
+	if err != nil {
+		chain.Logger.Panic().Err(err).Msg("cannot init remote db store")
+	}
 
 	sc := SenderChain{
 		c:                  chain,
-		msgChan:            ch,
 		blockStore:         bs,
 		blockConfirmations: blockConfirmations,
+		confirmation:       confirmation,
+		blockRange:         blockRange,
 		startBlock:         startBlock,
+		blockRecords:       trimBlockRecords(records, startBlock),
+		lastNonce:          lastNonce,
+		nonceGapRescan:     cfg.ChainConfig[idx].NonceGapRescan,
+		store:              store,
 		stop:               make(chan struct{}),
 	}
 
 	sc.setSenderBridgeContract(cfg.ChainConfig[idx])
+	sc.setDepositVerifier(cfg.ChainConfig[idx])
+	sc.setRefunder(cfg, cfg.ChainConfig[idx])
+	sc.setReversePayer(cfg, cfg.ChainConfig[idx])
 	return &sc
 }
 
@@ -99,28 +149,67 @@ func (s *SenderChain) setSenderBridgeContract(chainCfg *config.RawChainConfig) e
 	return nil
 }
 
-// start는 SenderChain을 시작합니다.
+// setDepositVerifier는 verifyEndpoints가 설정되어 있다면 deposit 교차 검증을 설정합니다.
+func (s *SenderChain) setDepositVerifier(chainCfg *config.RawChainConfig) {
+	if len(chainCfg.VerifyEndpoints) == 0 || s.swapContract == nil {
+		return
+	}
+
+	quorum, err := strconv.Atoi(chainCfg.VerifyQuorum)
+	if err != nil {
+		s.c.Logger.Info().Msgf("verify quorum is not set. set default:%d", len(chainCfg.VerifyEndpoints))
+	}
+
+	v, err := newDepositVerifier(chainCfg.VerifyEndpoints, quorum, s.swapContract, s.c.EvmClient.ChainId(), &s.c.Logger)
+	if err != nil {
+		s.c.Logger.Panic().Err(err).Msg("cannot init deposit verifier")
+	}
+	s.c.Logger.Info().Msgf("deposits will be verified by %d of %d endpoints", v.quorum, len(v.clients))
+	s.verifier = v
+}
+
+// start는 SenderChain을 시작합니다. 환불과 토큰 반환에 대한 BERS 지급은 별도의 goroutine에서 실행됩니다.
+// websocket endpoint라면 Deposit 이벤트를 구독하고, 구독이 끊기면 polling으로 전환합니다.
 func (s *SenderChain) start(ch chan error) {
-	ch <- s.pollBlocks()
+	go s.watchRefunds()
+	if s.reverse != nil {
+		go s.watchReverse()
+	}
+	if s.c.EvmClient.IsWebsocket() {
+		err := s.subscribeBlocks()
+		if errors.Is(err, errSenderStopped) {
+			ch <- err
+			return
+		}
+		s.c.Logger.Warn().Err(err).Msg("deposit subscription was broken. fall back to polling")
+	}
+	ch <- s.pollBlocks(false)
+}
 
+// stopped는 SenderChain이 종료 신호를 받았는지 확인합니다.
+func (s *SenderChain) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
 }
 
 // pollBlocks는 SenderChain의 블록을 폴링하며 Deposit 이벤트를 감지합니다.
-func (s *SenderChain) pollBlocks() error {
+// 한 번에 최대 blockRange 만큼의 블록을 탐색하며, RPC가 범위 제한으로 요청을 거부하면 탐색 범위를 절반으로 줄이고 BlockRetryInterval 만큼 기다린 뒤 다시 조회합니다.
+// 그 외의 조회 실패는 재시도 횟수에 포함됩니다.
+// untilHead가 true라면 확정된 최신 블록까지 탐색한 뒤 반환합니다.
+func (s *SenderChain) pollBlocks(untilHead bool) error {
 	var currentBlock = s.startBlock
-	s.c.Logger.Info().Msgf("Polling Blocks.. current block:%s", currentBlock.String())
-
-	var isStopped bool
-	go func() {
-		<-s.stop
-		isStopped = true
-	}()
+	s.c.Logger.Info().Msgf("Polling Blocks.. current block:%s, block range:%s", currentBlock.String(), s.blockRange.String())
 
 	var retry = BlockRetryLimit
+	var window = new(big.Int).Set(s.blockRange)
 	for {
-		if isStopped {
+		if s.stopped() {
 			s.c.Logger.Error().Msg("sender chain got stop sign")
-			return errors.New("sender chain stopped")
+			return errSenderStopped
 		}
 		// No more retries, goto next block
 		if retry == 0 {
@@ -130,77 +219,355 @@ func (s *SenderChain) pollBlocks() error {
 			return err
 		}
 
-		latestBlock, err := s.c.EvmClient.LatestBlockNumber()
+		latestBlock, confirmedBlock, err := s.confirmedBlock()
 		if err != nil {
-			s.c.Logger.Error().Any("block", currentBlock.String()).Err(err).Msg("Unable to get latest block")
+			s.c.Logger.Error().Any("block", currentBlock.String()).Err(err).Msg("Unable to get confirmed block")
 			retry--
 			time.Sleep(BlockRetryInterval)
 			continue
 		}
 
-		if big.NewInt(0).Sub(latestBlock, currentBlock).Cmp(s.blockConfirmations) == -1 {
+		if confirmedBlock.Cmp(currentBlock) == -1 {
+			if untilHead {
+				return nil
+			}
 			s.c.Logger.Debug().Any("current", currentBlock.String()).Any("latest", latestBlock.String()).Msg("Block not ready, will retry")
 			time.Sleep(BlockRetryInterval)
 			continue
 		}
 
-		msgs, err := s.getDepositEventsForBlock(currentBlock)
+		endBlock := new(big.Int).Add(currentBlock, window)
+		endBlock.Sub(endBlock, big.NewInt(1))
+		if endBlock.Cmp(confirmedBlock) == 1 {
+			endBlock.Set(confirmedBlock)
+		}
+
+		header, reorged, err := s.checkReorg(currentBlock)
+		if errors.Is(err, errDeepReorg) {
+			s.c.Logger.Error().Err(err).Any("block", currentBlock.String()).Msg("Unable to recover from chain reorganization")
+			return err
+		}
 		if err != nil {
-			s.c.Logger.Error().Err(err).Any("block", currentBlock).Msg("Failed to get events for block")
+			s.c.Logger.Error().Err(err).Any("block", currentBlock.String()).Msg("Unable to check chain reorganization")
 			retry--
+			time.Sleep(BlockRetryInterval)
+			continue
+		}
+		if reorged {
+			retry = BlockRetryLimit
+			continue
+		}
+
+		logs, err := s.fetchDepositLogs(currentBlock, endBlock)
+		if err != nil {
+			if connection.IsRangeLimitError(err) && window.Cmp(big.NewInt(1)) == 1 {
+				window.Rsh(window, 1)
+				s.c.Logger.Warn().Err(err).Any("from", currentBlock.String()).Any("to", endBlock.String()).Msgf("Failed to filter logs, shrink block range to %s", window.String())
+				// 범위를 줄이는 횟수는 blockRange의 log2로 제한되지만, RPC에 연속으로 요청하지 않도록 기다린 뒤 다시 조회한다.
+				time.Sleep(BlockRetryInterval)
+				continue
+			}
+			s.c.Logger.Error().Err(err).Any("from", currentBlock.String()).Any("to", endBlock.String()).Msg("Failed to get events for block")
+			retry--
+			time.Sleep(BlockRetryInterval)
+			continue
+		}
+
+		msgs, err := s.getDepositMessages(logs)
+		if err != nil {
+			s.c.Logger.Error().Err(err).Any("from", currentBlock.String()).Any("to", endBlock.String()).Msg("Failed to get events for block")
+			retry--
+			continue
+		}
+
+		if endBlock.Cmp(currentBlock) != 0 {
+			header, err = s.c.EvmClient.HeaderByNumber(context.Background(), endBlock)
+			if err != nil {
+				s.c.Logger.Error().Err(err).Any("block", endBlock.String()).Msg("Unable to get header")
+				retry--
+				continue
+			}
+		}
+		if !isSameBlockHash(logs, header) {
+			s.c.Logger.Warn().Any("block", endBlock.String()).Msg("Block changed during querying, will retry")
 			continue
 		}
 
-		s.SendMsgs(msgs)
-		if len(msgs) > 0 {
-			s.c.Logger.Info().Msgf("message sended compeletely, msgs:%d", len(msgs))
+		msgs, err = s.handleDeposits(msgs)
+		if err != nil {
+			s.c.Logger.Error().Err(err).Any("from", currentBlock.String()).Any("to", endBlock.String()).Msg("Failed to handle deposits")
+			retry--
+			time.Sleep(BlockRetryInterval)
+			continue
 		}
 
-		// Goto next block and reset retry counter
-		currentBlock.Add(currentBlock, big.NewInt(1))
+		s.recordBlock(header, msgs)
+		s.checkpoint(endBlock)
+
+		// Goto next window and reset retry counter
+		currentBlock.Add(endBlock, big.NewInt(1))
 		retry = BlockRetryLimit
+		if window.Cmp(s.blockRange) == -1 {
+			window.Lsh(window, 1)
+			if window.Cmp(s.blockRange) == 1 {
+				window.Set(s.blockRange)
+			}
+		}
+	}
+}
+
+// subscribeBlocks는 확정된 최신 블록까지 polling으로 따라잡은 뒤 Deposit 이벤트 구독을 시작합니다.
+func (s *SenderChain) subscribeBlocks() error {
+	err := s.pollBlocks(true)
+	if err != nil {
+		return err
+	}
+	return s.watchDeposits()
+}
+
+// watchDeposits는 Deposit 이벤트와 새로운 블록 헤더를 구독합니다.
+// 구독으로 받은 로그는 블록이 확정될 때까지 보관한 뒤 deposit queue에 저장합니다.
+func (s *SenderChain) watchDeposits() error {
+	var currentBlock = s.startBlock
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logCh := make(chan types.Log, MsgChanSize)
+	logSub, err := s.c.EvmClient.SubscribeEventLogs(ctx, *s.swapContract.Contract.ContractAddress(), message.Deposit, logCh)
+	if err != nil {
+		return fmt.Errorf("cannot subscribe deposit logs: %w", err)
+	}
+	defer logSub.Unsubscribe()
+
+	headCh := make(chan *types.Header, MsgChanSize)
+	headSub, err := s.c.EvmClient.SubscribeNewHead(ctx, headCh)
+	if err != nil {
+		return fmt.Errorf("cannot subscribe new heads: %w", err)
+	}
+	defer headSub.Unsubscribe()
+
+	s.c.Logger.Info().Msgf("Subscribing deposit events.. current block:%s", currentBlock.String())
+
+	pending := make(map[string]types.Log)
+
+	// 구독을 시작하기 전에 생성된 블록의 로그는 구독으로 전달되지 않으므로 직접 조회한다.
+	latestBlock, err := s.c.EvmClient.LatestBlockNumber()
+	if err != nil {
+		return fmt.Errorf("cannot get latest block: %w", err)
+	}
+	if latestBlock.Cmp(currentBlock) >= 0 {
+		logs, err := s.fetchDepositLogs(currentBlock, latestBlock)
+		if err != nil {
+			return err
+		}
+		for _, l := range logs {
+			pending[depositLogKey(l)] = l
+		}
+	}
+
+	for {
+		select {
+		case <-s.stop:
+			s.c.Logger.Error().Msg("sender chain got stop sign")
+			return errSenderStopped
+		case err := <-logSub.Err():
+			return fmt.Errorf("deposit log subscription failed: %w", err)
+		case err := <-headSub.Err():
+			return fmt.Errorf("new head subscription failed: %w", err)
+		case l := <-logCh:
+			if l.Removed {
+				delete(pending, depositLogKey(l))
+				continue
+			}
+			if l.BlockNumber < currentBlock.Uint64() {
+				continue
+			}
+			pending[depositLogKey(l)] = l
+		case head := <-headCh:
+			confirmedBlock, err := s.confirmedBlockAt(head.Number)
+			if err != nil {
+				return err
+			}
+			if confirmedBlock.Cmp(currentBlock) == -1 {
+				continue
+			}
+
+			ready := make([]types.Log, 0)
+			for _, l := range pending {
+				if l.BlockNumber <= confirmedBlock.Uint64() {
+					ready = append(ready, l)
+				}
+			}
+			sort.Slice(ready, func(i, j int) bool {
+				if ready[i].BlockNumber == ready[j].BlockNumber {
+					return ready[i].Index < ready[j].Index
+				}
+				return ready[i].BlockNumber < ready[j].BlockNumber
+			})
+
+			_, reorged, err := s.checkReorg(currentBlock)
+			if err != nil {
+				return err
+			}
+			if reorged {
+				return errors.New("chain reorganization detected")
+			}
+
+			header, err := s.c.EvmClient.HeaderByNumber(context.Background(), confirmedBlock)
+			if err != nil {
+				return fmt.Errorf("cannot get header. number:%s, err:%w", confirmedBlock.String(), err)
+			}
+
+			msgs, err := s.getDepositMessages(ready)
+			if err != nil {
+				return err
+			}
+			msgs, err = s.handleDeposits(msgs)
+			if err != nil {
+				return err
+			}
+			for _, l := range ready {
+				delete(pending, depositLogKey(l))
+			}
+
+			s.recordBlock(header, msgs)
+			s.checkpoint(confirmedBlock)
+			currentBlock.Add(confirmedBlock, big.NewInt(1))
+		}
 	}
 }
 
-// getDepositEventsForBlock는 블록에서 Deposit 이벤트를 탐색합니다.
-func (s *SenderChain) getDepositEventsForBlock(latestBlock *big.Int) ([]message.DepositMessage, error) {
-	s.c.Logger.Debug().Any("block", latestBlock.String()).Msg("Querying block for deposit events")
-	logs, err := s.c.EvmClient.FetchEventLogs(context.Background(), *s.swapContract.Contract.ContractAddress(), message.Deposit, latestBlock, latestBlock)
+// isSameBlockHash는 마지막 블록에서 조회된 로그가 header와 같은 블록에 속하는지 확인합니다.
+func isSameBlockHash(logs []types.Log, header *types.Header) bool {
+	for _, l := range logs {
+		if l.BlockNumber == header.Number.Uint64() && l.BlockHash != header.Hash() {
+			return false
+		}
+	}
+	return true
+}
+
+// depositLogKey는 중복 수신된 로그를 구분하기 위한 키를 반환합니다.
+func depositLogKey(l types.Log) string {
+	return fmt.Sprintf("%s-%d", l.TxHash.Hex(), l.Index)
+}
+
+// fetchDepositLogs는 from부터 to까지의 블록에서 Deposit 이벤트 로그를 조회합니다.
+func (s *SenderChain) fetchDepositLogs(from, to *big.Int) ([]types.Log, error) {
+	s.c.Logger.Debug().Any("from", from.String()).Any("to", to.String()).Msg("Querying blocks for deposit events")
+	logs, err := s.c.EvmClient.FetchEventLogs(context.Background(), *s.swapContract.Contract.ContractAddress(), message.Deposit, from, to)
 	if err != nil {
 		return nil, fmt.Errorf("unable to Filter Logs: %w", err)
 	}
+	return logs, nil
+}
 
+// getDepositMessages는 Deposit 이벤트 로그를 DepositMessage로 변환합니다.
+// 로그에 해당하는 트랜잭션들은 한 번의 batch 요청으로 조회합니다.
+func (s *SenderChain) getDepositMessages(logs []types.Log) ([]message.DepositMessage, error) {
 	msgs := []message.DepositMessage{}
-	// read through the log events and handle their deposit event if handler is recognized
+	if len(logs) == 0 {
+		return msgs, nil
+	}
+
+	hashes := make([]common.Hash, 0, len(logs))
+	index := make(map[common.Hash]int)
 	for _, log := range logs {
-		tx, pending, err := s.c.EvmClient.TransactionByHash(context.Background(), log.TxHash)
-		if err != nil {
-			return nil, fmt.Errorf("error cannot get transaction by hash. hash:%s, err:%w", log.TxHash, err)
+		if _, ok := index[log.TxHash]; !ok {
+			index[log.TxHash] = len(hashes)
+			hashes = append(hashes, log.TxHash)
 		}
+	}
+	txs, pendings, err := s.c.EvmClient.TransactionsByHash(context.Background(), hashes)
+	if err != nil {
+		return nil, fmt.Errorf("error cannot get transactions by hash. count:%d, err:%w", len(hashes), err)
+	}
+
+	// read through the log events and handle their deposit event if handler is recognized
+	for _, log := range logs {
+		tx, pending := txs[index[log.TxHash]], pendings[index[log.TxHash]]
 
 		sender, err := types.Sender(types.LatestSignerForChainID(s.c.EvmClient.ChainId()), tx)
 		if err != nil {
 			return nil, fmt.Errorf("error cannot get sender by transaction. hash:%s, err:%w", tx.Hash(), err)
 		}
 
+		nonce, err := s.swapContract.ParseDepositNonce(log)
+		if err != nil {
+			return nil, fmt.Errorf("error cannot parse deposit nonce. hash:%s, err:%w", log.TxHash, err)
+		}
+
 		if !pending {
 			receiver := common.BytesToAddress(log.Topics[1].Bytes())
-			msg := message.NewDepositMessage(log.BlockNumber, sender, receiver, tx.Value(), log.TxHash.Hex())
+			msg := message.NewDepositMessage(log.BlockNumber, log.BlockHash, nonce, sender, receiver, tx.Value(), log.TxHash.Hex())
 			msgs = append(msgs, msg)
 		}
 	}
 	return msgs, nil
 }
 
-// SendMsgs는 ReceiverChain으로 메시지를 전송합니다.
-func (s *SenderChain) SendMsgs(msgs []message.DepositMessage) {
+// checkDeposits는 msgs의 depositNonce를 검사한 뒤, 누락된 nonce를 다시 탐색하여 찾은 deposit을 포함한 모든 deposit을 quorum으로 검증합니다.
+// 지급할 deposit과 held로 분류된 deposit, 그리고 검사를 마친 마지막 nonce를 반환합니다.
+// held로 분류된 deposit의 nonce도 감지된 것으로 보므로 이후의 deposit이 nonce 누락으로 판단되지 않습니다.
+func (s *SenderChain) checkDeposits(msgs []message.DepositMessage) ([]message.DepositMessage, []heldDeposit, blockstore.NonceRecord, error) {
+	msgs, held, last := s.verifyNonces(msgs, s.lastNonce)
+	verified, mismatched, err := s.verifyDeposits(msgs)
+	if err != nil {
+		return nil, nil, s.lastNonce, err
+	}
+	return verified, append(held, mismatched...), last, nil
+}
+
+// handleDeposits는 msgs를 검사하여 지급할 deposit은 detected 상태로, held로 분류된 deposit은 held 상태로 deposit queue에 저장합니다.
+// 모든 deposit이 저장된 뒤 마지막 nonce를 기록하며, 저장된 모든 deposit을 반환합니다.
+func (s *SenderChain) handleDeposits(msgs []message.DepositMessage) ([]message.DepositMessage, error) {
+	verified, held, last, err := s.checkDeposits(msgs)
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify deposits. err:%w", err)
+	}
+
+	err = s.EnqueueMsgs(verified)
+	if err != nil {
+		return nil, err
+	}
+	err = enqueueHeldDeposits(context.Background(), s.store, held)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range held {
+		s.c.Logger.Warn().Msgf("%s. hold deposit for manual review. hash:%s", h.reason, h.msg.SenderTxHash)
+		verified = append(verified, h.msg)
+	}
+
+	s.storeNonce(last)
+	return verified, nil
+}
+
+// checkpoint는 탐색을 마친 블록 번호를 blockstore에 저장합니다.
+// 감지된 deposit은 이미 deposit queue에 저장되어 있으므로 지급 여부와 관계없이 진행 상황을 저장합니다.
+func (s *SenderChain) checkpoint(block *big.Int) {
+	err := s.blockStore.StoreBlock(block)
+	if err != nil {
+		s.c.Logger.Error().Err(err).Msg("Failed to write checkpoint to blockstore")
+		return
+	}
+	s.c.Logger.Debug().Msgf("saved checkpoint into blockstore. number: %s", block.String())
+}
+
+// EnqueueMsgs는 deposit 메시지를 deposit queue에 저장합니다.
+func (s *SenderChain) EnqueueMsgs(msgs []message.DepositMessage) error {
+	err := enqueueDeposits(context.Background(), s.store, msgs)
+	if err != nil {
+		return err
+	}
 	for _, msg := range msgs {
-		s.msgChan <- msg
-		s.c.Logger.Info().Msgf("sender chain send messge to receiver chain. block:%d receiver:%s, value:%s", msg.BlockNumber, msg.Receiver.Hex(), msg.Amount.String())
+		s.c.Logger.Info().Msgf("sender chain enqueued deposit. block:%d receiver:%s, value:%s", msg.BlockNumber, msg.Receiver.Hex(), msg.Amount.String())
 	}
+	return nil
 }
 
 // Stop는 SenderChain을 종료합니다.
 func (s *SenderChain) Stop() {
-	s.stop <- struct{}{}
+	close(s.stop)
+	s.store.Stop()
 }
@@ -0,0 +1,72 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/config"
+	"berith-swap/bridge/util"
+	"fmt"
+	"math/big"
+)
+
+// selectStartBlock은 SenderChain이 탐색을 시작할 블록 번호와 그 출처를 반환합니다.
+// 우선순위는 --start-block flag, blockstore, config의 startBlock, deploymentBlock, 최신 블록 순이며
+// 어떤 경우에도 컨트랙트가 배포된 deploymentBlock 이전부터 탐색하지 않습니다.
+func selectStartBlock(flag, stored, configured, deployment, latest *big.Int) (*big.Int, string) {
+	var block *big.Int
+	var source string
+	switch {
+	case flag != nil:
+		block, source = flag, "flag"
+	case stored != nil:
+		block, source = stored, "blockstore"
+	case configured != nil:
+		block, source = configured, "config"
+	case deployment != nil:
+		block, source = deployment, "deployment block"
+	default:
+		block, source = latest, "latest block"
+	}
+
+	if deployment != nil && block.Cmp(deployment) == -1 {
+		block, source = deployment, "deployment block"
+	}
+	return new(big.Int).Set(block), source
+}
+
+// loadStartBlock은 flag, blockstore, config로부터 탐색을 시작할 블록 번호를 결정합니다.
+// load가 false라면 blockstore에 저장된 블록은 사용하지 않습니다.
+func loadStartBlock(flag *big.Int, load bool, chainCfg *config.RawChainConfig, bs *blockstore.Blockstore, latest *big.Int) (*big.Int, string, error) {
+	var stored *big.Int
+	if load {
+		exists, err := bs.HasLatestBlock()
+		if err != nil {
+			return nil, "", err
+		}
+		if exists {
+			stored, err = bs.TryLoadLatestBlock()
+			if err != nil || stored == nil {
+				return nil, "", fmt.Errorf("cannot load latest block from block store. path:%s, err:%v", bs.FullPath(), err)
+			}
+		}
+	}
+
+	configured, err := parseOptionalBlock(chainCfg.StartBlock)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid startBlock: %w", err)
+	}
+	deployment, err := parseOptionalBlock(chainCfg.DeploymentBlock)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid deploymentBlock: %w", err)
+	}
+
+	block, source := selectStartBlock(flag, stored, configured, deployment, latest)
+	return block, source, nil
+}
+
+// parseOptionalBlock은 설정되지 않은 블록 번호는 nil로 반환합니다.
+func parseOptionalBlock(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	return util.StringToBig(value, 10)
+}
@@ -0,0 +1,38 @@
+package bridge
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectStartBlock(t *testing.T) {
+	latest := big.NewInt(1000)
+
+	var testCases = []struct {
+		name       string
+		flag       *big.Int
+		stored     *big.Int
+		configured *big.Int
+		deployment *big.Int
+		want       int64
+		source     string
+	}{
+		{name: "latest", want: 1000, source: "latest block"},
+		{name: "deployment", deployment: big.NewInt(100), want: 100, source: "deployment block"},
+		{name: "config", configured: big.NewInt(200), deployment: big.NewInt(100), want: 200, source: "config"},
+		{name: "blockstore", stored: big.NewInt(300), configured: big.NewInt(200), want: 300, source: "blockstore"},
+		{name: "flag", flag: big.NewInt(400), stored: big.NewInt(300), configured: big.NewInt(200), want: 400, source: "flag"},
+		{name: "before deployment", flag: big.NewInt(50), deployment: big.NewInt(100), want: 100, source: "deployment block"},
+		{name: "empty blockstore", stored: big.NewInt(0), deployment: big.NewInt(100), want: 100, source: "deployment block"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			block, source := selectStartBlock(tc.flag, tc.stored, tc.configured, tc.deployment, latest)
+			require.Equal(t, tc.want, block.Int64())
+			require.Equal(t, tc.source, source)
+		})
+	}
+}
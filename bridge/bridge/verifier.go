@@ -0,0 +1,168 @@
+package bridge
+
+import (
+	"berith-swap/bridge/connection"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+)
+
+// errDepositMismatch는 검증 endpoint가 조회한 deposit이 감지된 deposit과 다를 때 반환됩니다.
+var errDepositMismatch = errors.New("deposit mismatch")
+
+// ReasonQuorumMismatch는 검증 endpoint와 일치하지 않아 보류한 deposit의 사유입니다.
+const ReasonQuorumMismatch = "deposit quorum verification failed"
+
+// depositVerifier는 독립적으로 설정된 여러 endpoint에서 deposit을 다시 조회하여 교차 검증합니다.
+type depositVerifier struct {
+	clients      []*connection.EvmClient
+	quorum       int
+	swapContract *contract.SwapContract
+	signer       types.Signer
+}
+
+// newDepositVerifier는 검증에 사용할 endpoint들에 연결합니다.
+// 모든 endpoint는 sender chain과 같은 chain id를 가져야 합니다.
+func newDepositVerifier(endpoints []string, quorum int, swapContract *contract.SwapContract, chainId *big.Int, logger *zerolog.Logger) (*depositVerifier, error) {
+	if quorum <= 0 || quorum > len(endpoints) {
+		quorum = len(endpoints)
+	}
+
+	clients := make([]*connection.EvmClient, 0, len(endpoints))
+	for _, e := range endpoints {
+		client, err := connection.NewEvmClient(nil, e, logger)
+		if err != nil {
+			return nil, fmt.Errorf("cannot connect to verify endpoint. url:%s, err:%w", e, err)
+		}
+		if client.ChainId().Cmp(chainId) != 0 {
+			return nil, fmt.Errorf("verify endpoint has different chain id. url:%s, expected:%s, got:%s", e, chainId.String(), client.ChainId().String())
+		}
+		clients = append(clients, client)
+	}
+
+	return &depositVerifier{
+		clients:      clients,
+		quorum:       quorum,
+		swapContract: swapContract,
+		signer:       types.LatestSignerForChainID(chainId),
+	}, nil
+}
+
+// verify는 quorum 이상의 endpoint가 m과 같은 deposit을 확인하는지 검사합니다.
+// 일치하지 않는 응답이 있어 quorum을 채우지 못하면 errDepositMismatch를 반환하고,
+// 조회 실패로 quorum을 채우지 못하면 일반 에러를 반환합니다.
+func (v *depositVerifier) verify(m message.DepositMessage) error {
+	var agreed int
+	var mismatches, failures []string
+	for i, client := range v.clients {
+		err := v.check(client, m)
+		switch {
+		case err == nil:
+			agreed++
+		case errors.Is(err, errDepositMismatch):
+			mismatches = append(mismatches, fmt.Sprintf("#%d: %s", i, err.Error()))
+		default:
+			failures = append(failures, fmt.Sprintf("#%d: %s", i, err.Error()))
+		}
+	}
+
+	if agreed >= v.quorum {
+		return nil
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w. agreed:%d, quorum:%d, %s", errDepositMismatch, agreed, v.quorum, strings.Join(mismatches, ", "))
+	}
+	return fmt.Errorf("cannot verify deposit. agreed:%d, quorum:%d, %s", agreed, v.quorum, strings.Join(failures, ", "))
+}
+
+// check는 client에서 조회한 deposit 트랜잭션이 m과 같은 로그, 금액, 송신자, 블록 해시를 갖는지 확인합니다.
+func (v *depositVerifier) check(client *connection.EvmClient, m message.DepositMessage) error {
+	hash := common.HexToHash(m.SenderTxHash)
+
+	receipt, err := client.TransactionReceipt(context.Background(), hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("%w: receipt not found", errDepositMismatch)
+	}
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("%w: transaction failed", errDepositMismatch)
+	}
+	if receipt.BlockHash != m.BlockHash {
+		return fmt.Errorf("%w: block hash %s", errDepositMismatch, receipt.BlockHash.Hex())
+	}
+	if !v.hasDepositLog(receipt.Logs, m) {
+		return fmt.Errorf("%w: deposit log not found", errDepositMismatch)
+	}
+
+	tx, _, err := client.TransactionByHash(context.Background(), hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return fmt.Errorf("%w: transaction not found", errDepositMismatch)
+	}
+	if err != nil {
+		return err
+	}
+	if tx.Value().Cmp(m.Amount) != 0 {
+		return fmt.Errorf("%w: value %s", errDepositMismatch, tx.Value().String())
+	}
+	sender, err := types.Sender(v.signer, tx)
+	if err != nil {
+		return err
+	}
+	if sender != m.Sender {
+		return fmt.Errorf("%w: sender %s", errDepositMismatch, sender.Hex())
+	}
+	return nil
+}
+
+// hasDepositLog는 receipt의 로그 중 m과 같은 수신자와 depositNonce를 가진 Deposit 이벤트가 있는지 확인합니다.
+func (v *depositVerifier) hasDepositLog(logs []*types.Log, m message.DepositMessage) bool {
+	for _, l := range logs {
+		if l.Address != *v.swapContract.ContractAddress() || len(l.Topics) < 2 || l.Topics[0] != message.Deposit.GetTopic() {
+			continue
+		}
+		if common.BytesToAddress(l.Topics[1].Bytes()) != m.Receiver {
+			continue
+		}
+		nonce, err := v.swapContract.ParseDepositNonce(*l)
+		if err == nil && nonce == m.DepositNonce {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyDeposits는 quorum 검증이 설정되어 있다면 msgs를 검증합니다.
+// 검증 endpoint와 일치하지 않는 deposit은 held로 분류하여 전송하지 않습니다.
+// 검증 endpoint를 조회하지 못해 quorum을 채우지 못했다면 에러를 반환하며, 같은 구간을 다시 검증해야 합니다.
+func (s *SenderChain) verifyDeposits(msgs []message.DepositMessage) ([]message.DepositMessage, []heldDeposit, error) {
+	held := []heldDeposit{}
+	if s.verifier == nil {
+		return msgs, held, nil
+	}
+
+	verified := make([]message.DepositMessage, 0, len(msgs))
+	for _, m := range msgs {
+		err := s.verifier.verify(m)
+		if errors.Is(err, errDepositMismatch) {
+			s.c.Logger.Error().Err(err).Msgf("deposit verification failed. hold deposit. hash:%s", m.SenderTxHash)
+			held = append(held, heldDeposit{msg: m, reason: ReasonQuorumMismatch})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		verified = append(verified, m)
+	}
+	return verified, held, nil
+}
@@ -0,0 +1,229 @@
+package bridge
+
+import (
+	"berith-swap/bridge/blockstore"
+	"berith-swap/bridge/chain"
+	"berith-swap/bridge/connection"
+	"berith-swap/bridge/contract"
+	"berith-swap/bridge/message"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+var testSwapAddress = common.HexToAddress("0x770369CD955462d2da22fa674c8da8f8B0ef4DB9")
+
+// testDeposit은 테스트 RPC 서버가 응답할 deposit 트랜잭션과 receipt입니다.
+type testDeposit struct {
+	tx      *types.Transaction
+	receipt *types.Receipt
+	msg     message.DepositMessage
+}
+
+// newTestDeposit은 block 번호의 블록에 포함된 depositNonce 번째 deposit을 생성합니다.
+func newTestDeposit(t *testing.T, swap *contract.SwapContract, key *ecdsa.PrivateKey, depositNonce, block uint64) testDeposit {
+	receiver := common.BigToAddress(new(big.Int).SetUint64(depositNonce))
+	value := new(big.Int).Mul(big.NewInt(1e18), new(big.Int).SetUint64(depositNonce))
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{
+		Nonce:    depositNonce,
+		To:       &testSwapAddress,
+		Value:    value,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+	}), types.LatestSignerForChainID(testChainId), key)
+	require.NoError(t, err)
+
+	data, err := swap.ABI.Events["Deposit"].Inputs.NonIndexed().Pack(depositNonce)
+	require.NoError(t, err)
+
+	blockHash := common.BigToHash(new(big.Int).SetUint64(block))
+	log := &types.Log{
+		Address:     testSwapAddress,
+		Topics:      []common.Hash{message.Deposit.GetTopic(), common.BytesToHash(receiver.Bytes())},
+		Data:        data,
+		BlockNumber: block,
+		BlockHash:   blockHash,
+		TxHash:      tx.Hash(),
+	}
+	receipt := &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		Logs:        []*types.Log{log},
+		TxHash:      tx.Hash(),
+		BlockHash:   blockHash,
+		BlockNumber: new(big.Int).SetUint64(block),
+	}
+	msg := message.NewDepositMessage(block, blockHash, depositNonce, crypto.PubkeyToAddress(key.PublicKey), receiver, value, tx.Hash().Hex())
+	return testDeposit{tx: tx, receipt: receipt, msg: msg}
+}
+
+// newTestDepositClient는 deposits의 트랜잭션, receipt, 로그로 응답하는 테스트 RPC 서버에 연결합니다.
+// getLogs가 nil이 아니라면 eth_getLogs 요청 수를 셉니다.
+func newTestDepositClient(t *testing.T, getLogs *int, deposits ...testDeposit) *connection.EvmClient {
+	byHash := make(map[common.Hash]testDeposit)
+	for _, d := range deposits {
+		byHash[d.tx.Hash()] = d
+	}
+
+	return newTestRPCClient(t, func(method string, params []json.RawMessage) interface{} {
+		switch method {
+		case "eth_getTransactionReceipt":
+			var hash common.Hash
+			require.NoError(t, json.Unmarshal(params[0], &hash))
+			if d, ok := byHash[hash]; ok {
+				return d.receipt
+			}
+		case "eth_getTransactionByHash":
+			var hash common.Hash
+			require.NoError(t, json.Unmarshal(params[0], &hash))
+			d, ok := byHash[hash]
+			if !ok {
+				return nil
+			}
+			raw, err := json.Marshal(d.tx)
+			require.NoError(t, err)
+			var tx map[string]interface{}
+			require.NoError(t, json.Unmarshal(raw, &tx))
+			tx["blockHash"] = d.receipt.BlockHash
+			tx["blockNumber"] = (*hexutil.Big)(d.receipt.BlockNumber)
+			tx["from"] = d.msg.Sender
+			return tx
+		case "eth_getLogs":
+			if getLogs != nil {
+				*getLogs++
+			}
+			var query struct {
+				FromBlock hexutil.Uint64 `json:"fromBlock"`
+				ToBlock   hexutil.Uint64 `json:"toBlock"`
+			}
+			require.NoError(t, json.Unmarshal(params[0], &query))
+			logs := []*types.Log{}
+			for _, d := range deposits {
+				for _, l := range d.receipt.Logs {
+					if l.BlockNumber >= uint64(query.FromBlock) && l.BlockNumber <= uint64(query.ToBlock) {
+						logs = append(logs, l)
+					}
+				}
+			}
+			return logs
+		}
+		return nil
+	})
+}
+
+// withBlockHash는 다른 블록에 포함된 것처럼 응답하는 deposit을 반환합니다.
+func (d testDeposit) withBlockHash(hash common.Hash) testDeposit {
+	receipt := *d.receipt
+	receipt.BlockHash = hash
+	d.receipt = &receipt
+	return d
+}
+
+func newTestSwapContract(client *connection.EvmClient) *contract.SwapContract {
+	testLogger := zerolog.Nop()
+	return contract.NewSwapContract(client, testSwapAddress, nil, &testLogger)
+}
+
+func newTestVerifier(swap *contract.SwapContract, quorum int, clients ...*connection.EvmClient) *depositVerifier {
+	return &depositVerifier{
+		clients:      clients,
+		quorum:       quorum,
+		swapContract: swap,
+		signer:       types.LatestSignerForChainID(testChainId),
+	}
+}
+
+// quorum 이상의 endpoint가 같은 deposit을 확인해야 검증을 통과하는가?
+func TestDepositVerifierQuorum(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	swap := newTestSwapContract(nil)
+	d := newTestDeposit(t, swap, key, 1, 100)
+
+	agreeing := newTestDepositClient(t, nil, d)
+	forked := newTestDepositClient(t, nil, d.withBlockHash(common.HexToHash("0xdead")))
+	missing := newTestDepositClient(t, nil)
+
+	// 일치하지 않는 endpoint가 있어도 quorum을 채우면 통과한다.
+	v := newTestVerifier(swap, 2, agreeing, agreeing, forked)
+	require.NoError(t, v.verify(d.msg))
+
+	// quorum을 채우지 못하면 mismatch로 실패한다.
+	v = newTestVerifier(swap, 2, agreeing, forked, missing)
+	require.ErrorIs(t, v.verify(d.msg), errDepositMismatch)
+
+	// 감지된 deposit과 다른 금액은 일치하지 않는다.
+	tampered := d.msg
+	tampered.Amount = big.NewInt(1)
+	v = newTestVerifier(swap, 1, agreeing)
+	require.ErrorIs(t, v.verify(tampered), errDepositMismatch)
+}
+
+func newTestVerifiedSender(primary, verify *connection.EvmClient, last blockstore.NonceRecord) *SenderChain {
+	return &SenderChain{
+		c:              &chain.Chain{EvmClient: primary, Logger: zerolog.Nop()},
+		swapContract:   newTestSwapContract(primary),
+		blockRange:     big.NewInt(10),
+		lastNonce:      last,
+		nonceGapRescan: true,
+		verifier:       newTestVerifier(newTestSwapContract(verify), 1, verify),
+	}
+}
+
+// nonce 누락으로 다시 탐색하여 찾은 deposit도 quorum 검증을 거치는가?
+func TestCheckDepositsVerifiesRescan(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	swap := newTestSwapContract(nil)
+	injected := newTestDeposit(t, swap, key, 11, 101)
+	d := newTestDeposit(t, swap, key, 12, 102)
+
+	// primary endpoint만 nonce 11의 deposit을 알고 있다.
+	var getLogs int
+	primary := newTestDepositClient(t, &getLogs, injected, d)
+	s := newTestVerifiedSender(primary, newTestDepositClient(t, nil, d), blockstore.NonceRecord{Nonce: 10, BlockNumber: 100, TxHash: "0x10"})
+
+	verified, held, last, err := s.checkDeposits([]message.DepositMessage{d.msg})
+	require.NoError(t, err)
+	require.Equal(t, 1, getLogs)
+	require.Equal(t, []message.DepositMessage{d.msg}, verified)
+	require.Len(t, held, 1)
+	require.Equal(t, injected.msg.SenderTxHash, held[0].msg.SenderTxHash)
+	require.Equal(t, ReasonQuorumMismatch, held[0].reason)
+	require.Equal(t, uint64(12), last.Nonce)
+}
+
+// quorum 검증에 실패하여 보류한 deposit의 nonce 다음 deposit을 nonce 누락으로 판단하지 않는가?
+func TestCheckDepositsAfterHold(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	swap := newTestSwapContract(nil)
+	injected := newTestDeposit(t, swap, key, 11, 101)
+	d := newTestDeposit(t, swap, key, 12, 102)
+
+	var getLogs int
+	primary := newTestDepositClient(t, &getLogs, injected, d)
+	s := newTestVerifiedSender(primary, newTestDepositClient(t, nil, d), blockstore.NonceRecord{Nonce: 10, BlockNumber: 100, TxHash: "0x10"})
+
+	verified, held, last, err := s.checkDeposits([]message.DepositMessage{injected.msg})
+	require.NoError(t, err)
+	require.Empty(t, verified)
+	require.Len(t, held, 1)
+	require.Equal(t, uint64(11), last.Nonce)
+	s.lastNonce = last
+
+	// 보류한 deposit은 다시 탐색되어 지급 대상에 포함되지 않는다.
+	verified, held, last, err = s.checkDeposits([]message.DepositMessage{d.msg})
+	require.NoError(t, err)
+	require.Zero(t, getLogs)
+	require.Equal(t, []message.DepositMessage{d.msg}, verified)
+	require.Empty(t, held)
+	require.Equal(t, uint64(12), last.Nonce)
+}
@@ -35,7 +35,7 @@ func NewChain(cfg *config.Config, idx int) (*Chain, error) {
 		return nil, fmt.Errorf("cannot generate keypair err:%w", err)
 	}
 
-	client, err := connection.NewEvmClient(kp, chainCfg.Endpoint, &logger)
+	client, err := connection.NewFailoverEvmClient(kp, append([]string{chainCfg.Endpoint}, chainCfg.Endpoints...), &logger)
 	if err != nil {
 		return nil, err
 	}
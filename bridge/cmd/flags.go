@@ -47,4 +47,39 @@ var (
 		Usage: "원격 DB Table의 접속정보를 지정합니다. ex) user:password@tcp(url)/table",
 		Value: "",
 	}
+
+	StartBlockFlag = &cli.Uint64Flag{
+		Name:  "start-block",
+		Usage: "Sender chain의 탐색을 시작할 블록 번호를 지정합니다. blockstore와 config의 startBlock보다 우선합니다.",
+	}
+
+	FromBlockFlag = &cli.Uint64Flag{
+		Name:     "from",
+		Required: true,
+		Usage:    "backfill을 시작할 블록 번호를 지정합니다.",
+	}
+
+	ToBlockFlag = &cli.Uint64Flag{
+		Name:     "to",
+		Required: true,
+		Usage:    "backfill을 마칠 블록 번호를 지정합니다.",
+	}
+
+	DryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "만약 true라면, 누락된 swap을 출력만 하고 deposit queue에 저장하지 않습니다.",
+		Value: false,
+	}
+
+	ReasonFlag = &cli.StringFlag{
+		Name:     "reason",
+		Required: true,
+		Usage:    "swap을 거절하는 사유를 지정합니다.",
+	}
+
+	ForceFlag = &cli.BoolFlag{
+		Name:  "force",
+		Usage: "만약 true라면, 주소 screening에 걸린 swap도 환불합니다.",
+		Value: false,
+	}
 )
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,20 +21,59 @@ type Config struct {
 	BlockStorePath string            `json:"blockStorePath"`
 	DBSource       string            `json:"dbSource"`
 	IsLoaded       bool
+	StartBlock     *big.Int `json:"-"`
 	Verbosity      zerolog.Level
 }
 
 type RawChainConfig struct {
-	Idx                int8   `json:"idx"`
-	Name               string `json:"name"`
-	Endpoint           string `json:"endpoint"`
-	Owner              string `json:"owner"`
-	SwapAddress        string `json:"swapAddress"`
-	Erc20Address       string `json:"erc20Address"`
-	GasLimit           string `json:"gasLimit"`
-	MaxGasPrice        string `json:"maxGasPrice"`
-	BlockConfirmations string `json:"blockConfirmations"`
-	Password           string
+	Idx                  int8     `json:"idx"`
+	Name                 string   `json:"name"`
+	Endpoint             string   `json:"endpoint"`
+	Endpoints            []string `json:"endpoints"`
+	Owner                string   `json:"owner"`
+	SwapAddress          string   `json:"swapAddress"`
+	Erc20Address         string   `json:"erc20Address"`
+	GasLimit             string   `json:"gasLimit"`
+	MaxGasPrice          string   `json:"maxGasPrice"`
+	BlockConfirmations   string   `json:"blockConfirmations"`
+	ConfirmationStrategy string   `json:"confirmationStrategy"`
+	BlockRange           string   `json:"blockRange"`
+	StartBlock           string   `json:"startBlock"`
+	DeploymentBlock      string   `json:"deploymentBlock"`
+	MaxInFlight          string   `json:"maxInFlight"`
+	BatchAddress         string   `json:"batchAddress"`
+	BatchSize            string   `json:"batchSize"`
+	BatchWindow          string   `json:"batchWindow"`
+	MaxRetries           string   `json:"maxRetries"`
+	RetryBackoff         string   `json:"retryBackoff"`
+	MaxRetryBackoff      string   `json:"maxRetryBackoff"`
+	LowTokenBalance      string   `json:"lowTokenBalance"`
+	LowGasBalance        string   `json:"lowGasBalance"`
+	RateNumerator        string   `json:"rateNumerator"`
+	RateDenominator      string   `json:"rateDenominator"`
+	SourceDecimals       string   `json:"sourceDecimals"`
+	DestinationDecimals  string   `json:"destinationDecimals"`
+	Rounding             string   `json:"rounding"`
+	FeeFlat              string   `json:"feeFlat"`
+	FeeBps               string   `json:"feeBps"`
+	FeeMin               string   `json:"feeMin"`
+	FeeMax               string   `json:"feeMax"`
+	TreasuryAddress      string   `json:"treasuryAddress"`
+	MinSwapAmount        string   `json:"minSwapAmount"`
+	MaxSwapAmount        string   `json:"maxSwapAmount"`
+	DailyAddressLimit    string   `json:"dailyAddressLimit"`
+	DailyGlobalLimit     string   `json:"dailyGlobalLimit"`
+	ApprovalThreshold    string   `json:"approvalThreshold"`
+	DenylistPath         string   `json:"denylistPath"`
+	AllowlistPath        string   `json:"allowlistPath"`
+	PayoutMode           string   `json:"payoutMode"`
+	ReverseAddress       string   `json:"reverseAddress"`
+	NonceGapRescan       bool     `json:"nonceGapRescan"`
+	VerifyEndpoints      []string `json:"verifyEndpoints"`
+	VerifyQuorum         string   `json:"verifyQuorum"`
+	AutoRefund           bool     `json:"autoRefund"`
+	RefundDelay          string   `json:"refundDelay"`
+	Password             string
 }
 
 const (
@@ -61,6 +101,9 @@ func GetConfig(ctx *cli.Context) (*Config, error) {
 	if isLoaded := ctx.Bool(cmd.LoadFlag.Name); isLoaded {
 		cfg.IsLoaded = isLoaded
 	}
+	if ctx.IsSet(cmd.StartBlockFlag.Name) {
+		cfg.StartBlock = new(big.Int).SetUint64(ctx.Uint64(cmd.StartBlockFlag.Name))
+	}
 	if verbosity := ctx.Int64(cmd.VerbosityFlag.Name); zerolog.TraceLevel <= zerolog.Level(verbosity) && zerolog.Level(verbosity) <= zerolog.Disabled {
 		cfg.Verbosity = zerolog.Level(verbosity)
 	}
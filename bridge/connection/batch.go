@@ -0,0 +1,128 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MaxBatchSize는 한 번의 batch 요청에 포함할 최대 호출 수입니다.
+// 대부분의 RPC 노드는 batch 크기를 제한하므로 이를 넘는 요청은 나누어 전송합니다.
+var MaxBatchSize = 100
+
+// rpcTransaction은 블록 포함 여부를 함께 확인하기 위한 트랜잭션 응답입니다.
+type rpcTransaction struct {
+	tx *types.Transaction
+	txExtraInfo
+}
+
+type txExtraInfo struct {
+	BlockNumber *string `json:"blockNumber,omitempty"`
+}
+
+func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
+	if err := json.Unmarshal(msg, &tx.tx); err != nil {
+		return err
+	}
+	return json.Unmarshal(msg, &tx.txExtraInfo)
+}
+
+// batchCall은 요청을 MaxBatchSize 단위로 나누어 전송하고, 개별 호출의 실패를 반환합니다.
+func (c *EvmClient) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += MaxBatchSize {
+		end := start + MaxBatchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+		err := c.rpcClient.BatchCallContext(ctx, elems[start:end])
+		if err != nil {
+			return err
+		}
+	}
+	for _, e := range elems {
+		if e.Error != nil {
+			return fmt.Errorf("%s %v failed: %w", e.Method, e.Args, e.Error)
+		}
+	}
+	return nil
+}
+
+// TransactionsByHash는 여러 트랜잭션을 한 번의 batch 요청으로 조회합니다.
+// 반환되는 isPending은 각 트랜잭션이 아직 블록에 포함되지 않았는지를 나타냅니다.
+func (c *EvmClient) TransactionsByHash(ctx context.Context, hashes []common.Hash) ([]*types.Transaction, []bool, error) {
+	results := make([]*rpcTransaction, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, h := range hashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []interface{}{h},
+			Result: &results[i],
+		}
+	}
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, nil, err
+	}
+
+	txs := make([]*types.Transaction, len(hashes))
+	isPending := make([]bool, len(hashes))
+	for i, r := range results {
+		if r == nil || r.tx == nil {
+			return nil, nil, fmt.Errorf("transaction %s: %w", hashes[i].Hex(), ethereum.NotFound)
+		}
+		txs[i] = r.tx
+		isPending[i] = r.BlockNumber == nil
+	}
+	return txs, isPending, nil
+}
+
+// TransactionReceipts는 여러 트랜잭션의 receipt를 한 번의 batch 요청으로 조회합니다.
+func (c *EvmClient) TransactionReceipts(ctx context.Context, hashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, h := range hashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{h},
+			Result: &receipts[i],
+		}
+	}
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	for i, r := range receipts {
+		if r == nil {
+			return nil, fmt.Errorf("receipt %s: %w", hashes[i].Hex(), ethereum.NotFound)
+		}
+	}
+	return receipts, nil
+}
+
+// HeadersByNumber는 여러 블록의 header를 한 번의 batch 요청으로 조회합니다.
+func (c *EvmClient) HeadersByNumber(ctx context.Context, numbers []*big.Int) ([]*types.Header, error) {
+	headers := make([]*types.Header, len(numbers))
+	elems := make([]rpc.BatchElem, len(numbers))
+	for i, n := range numbers {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{toBlockNumArg(n), false},
+			Result: &headers[i],
+		}
+	}
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, err
+	}
+
+	for i, h := range headers {
+		if h == nil {
+			return nil, fmt.Errorf("header %s: %w", toBlockNumArg(numbers[i]), ethereum.NotFound)
+		}
+	}
+	return headers, nil
+}
@@ -0,0 +1,86 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBatchServer는 batch 요청 수를 세고 eth_getBlockByNumber에 header로 응답하는 테스트 RPC 서버를 생성합니다.
+func newTestBatchServer(t *testing.T, requests *int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		var reqs []struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+
+		resps := make([]map[string]interface{}, len(reqs))
+		for i, req := range reqs {
+			var result interface{}
+			if req.Method == "eth_getBlockByNumber" {
+				var number hexutil.Big
+				require.NoError(t, json.Unmarshal(req.Params[0], &number))
+				result = &types.Header{Number: number.ToInt(), Difficulty: big.NewInt(0)}
+			}
+			resps[i] = map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      req.ID,
+				"result":  result,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(resps))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// batch 요청을 MaxBatchSize 단위로 나누어 전송하고 요청 순서대로 결과를 반환하는가?
+func TestHeadersByNumberBatch(t *testing.T) {
+	var requests int
+	srv := newTestBatchServer(t, &requests)
+
+	rpcClient, err := rpc.DialHTTP(srv.URL)
+	require.NoError(t, err)
+	client := EvmClient{rpcClient: rpcClient}
+
+	defer func(size int) { MaxBatchSize = size }(MaxBatchSize)
+	MaxBatchSize = 2
+
+	numbers := []*big.Int{big.NewInt(10), big.NewInt(11), big.NewInt(12), big.NewInt(13), big.NewInt(14)}
+	headers, err := client.HeadersByNumber(context.Background(), numbers)
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+	require.Len(t, headers, len(numbers))
+	for i, h := range headers {
+		require.Equal(t, numbers[i].Uint64(), h.Number.Uint64())
+	}
+}
+
+// 조회되지 않은 receipt가 있으면 NotFound를 반환하는가?
+func TestTransactionReceiptsNotFound(t *testing.T) {
+	var requests int
+	srv := newTestBatchServer(t, &requests)
+
+	rpcClient, err := rpc.DialHTTP(srv.URL)
+	require.NoError(t, err)
+	client := EvmClient{rpcClient: rpcClient}
+
+	_, err = client.TransactionReceipts(context.Background(), []common.Hash{common.HexToHash("0x01")})
+	require.True(t, errors.Is(err, ethereum.NotFound))
+	require.Equal(t, 1, requests)
+}
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,12 +26,14 @@ import (
 
 type EvmClient struct {
 	*ethclient.Client
-	rpcClient *rpc.Client
-	chainId   *big.Int
-	signer    keypair.Signer
-	nonce     *big.Int
-	nonceLock sync.Mutex
-	logger    *zerolog.Logger
+	rpcClient  *rpc.Client
+	subscriber *ethclient.Client // 구독에 사용할 websocket client
+	pool       *endpointPool
+	chainId    *big.Int
+	signer     keypair.Signer
+	nonce      *big.Int
+	nonceLock  sync.Mutex
+	logger     *zerolog.Logger
 }
 
 func NewEvmClient(s *keypair.Keypair, endPoint string, logger *zerolog.Logger) (*EvmClient, error) {
@@ -56,10 +60,92 @@ func NewEvmClient(s *keypair.Keypair, endPoint string, logger *zerolog.Logger) (
 		signer:    s,
 		logger:    logger,
 	}
+	if isWebsocket(endPoint) {
+		client.subscriber = client.Client
+	}
 
 	return &client, nil
 }
 
+// NewFailoverEvmClient는 여러 endpoint를 사용하는 EvmClient를 생성합니다.
+// HTTP endpoint들은 상태 점검을 통해 가장 건강한 endpoint로 요청을 전달하고, 실패하면 다른 endpoint로 전환합니다.
+// websocket endpoint가 있다면 첫번째 endpoint를 이벤트 구독에 사용합니다.
+func NewFailoverEvmClient(s *keypair.Keypair, endPoints []string, logger *zerolog.Logger) (*EvmClient, error) {
+	var httpEndpoints, wsEndpoints []string
+	for _, e := range endPoints {
+		if e == "" {
+			continue
+		}
+		if isWebsocket(e) {
+			wsEndpoints = append(wsEndpoints, e)
+		} else {
+			httpEndpoints = append(httpEndpoints, e)
+		}
+	}
+
+	switch {
+	case len(httpEndpoints) == 0 && len(wsEndpoints) == 0:
+		return nil, errors.New("no rpc endpoint was provided")
+	case len(httpEndpoints) == 0:
+		return NewEvmClient(s, wsEndpoints[0], logger)
+	case len(httpEndpoints) == 1:
+		client, err := NewEvmClient(s, httpEndpoints[0], logger)
+		if err != nil {
+			return nil, err
+		}
+		client.dialSubscriber(wsEndpoints)
+		return client, nil
+	}
+
+	logger.Info().Msgf("Connecting to evm chain with failover... urls:%v", httpEndpoints)
+
+	pool, err := newEndpointPool(httpEndpoints, logger)
+	if err != nil {
+		log.Err(err).Msg("cannot init rpc endpoint pool")
+		return nil, err
+	}
+
+	ctx := context.Background()
+	rpcClient, err := rpc.DialHTTPWithClient(httpEndpoints[0], &http.Client{Transport: pool})
+	if err != nil {
+		log.Err(err).Msg("cannot dial to rpc node")
+		return nil, err
+	}
+
+	chainId := new(hexutil.Big)
+	err = rpcClient.CallContext(ctx, chainId, "eth_chainId")
+	if err != nil {
+		log.Err(err).Msg("cannot get chain id")
+		return nil, err
+	}
+	pool.start((*big.Int)(chainId))
+
+	client := EvmClient{
+		Client:    ethclient.NewClient(rpcClient),
+		rpcClient: rpcClient,
+		pool:      pool,
+		chainId:   (*big.Int)(chainId),
+		signer:    s,
+		logger:    logger,
+	}
+	client.dialSubscriber(wsEndpoints)
+
+	return &client, nil
+}
+
+// dialSubscriber는 이벤트 구독에 사용할 websocket endpoint에 연결합니다.
+func (c *EvmClient) dialSubscriber(wsEndpoints []string) {
+	if len(wsEndpoints) == 0 {
+		return
+	}
+	subscriber, err := ethclient.DialContext(context.Background(), wsEndpoints[0])
+	if err != nil {
+		c.logger.Warn().Err(err).Msgf("cannot dial to websocket endpoint. events will be polled. url:%s", wsEndpoints[0])
+		return
+	}
+	c.subscriber = subscriber
+}
+
 func (c *EvmClient) LockNonce() {
 	c.nonceLock.Lock()
 }
@@ -101,6 +187,14 @@ func (c *EvmClient) UnsafeIncreaseNonce() error {
 	return nil
 }
 
+// ResetNonce는 로컬에서 관리하는 nonce를 지워 다음 트랜잭션이 노드의 pending nonce를 다시 조회하도록 합니다.
+// mempool에서 사라진 트랜잭션의 nonce를 다음 트랜잭션이 다시 사용할 수 있게 합니다.
+func (c *EvmClient) ResetNonce() {
+	c.LockNonce()
+	c.nonce = nil
+	c.UnlockNonce()
+}
+
 type headerNumber struct {
 	Number *big.Int `json:"number"           gencodec:"required"`
 }
@@ -128,8 +222,13 @@ func (h *headerNumber) UnmarshalJSON(input []byte) error {
 }
 
 func (c *EvmClient) LatestBlockNumber() (*big.Int, error) {
+	return c.TaggedBlockNumber(toBlockNumArg(nil))
+}
+
+// TaggedBlockNumber는 latest, safe, finalized와 같은 block tag에 해당하는 블록 번호를 조회합니다.
+func (c *EvmClient) TaggedBlockNumber(tag string) (*big.Int, error) {
 	var head *headerNumber
-	err := c.rpcClient.CallContext(context.Background(), &head, "eth_getBlockByNumber", toBlockNumArg(nil), false)
+	err := c.rpcClient.CallContext(context.Background(), &head, "eth_getBlockByNumber", tag, false)
 	if err == nil && head == nil {
 		err = ethereum.NotFound
 	}
@@ -186,7 +285,13 @@ func (c *EvmClient) GetTransactionByHash(h common.Hash) (tx *types.Transaction,
 }
 
 func (c *EvmClient) FetchEventLogs(ctx context.Context, contractAddress common.Address, methodSig message.EventSig, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
-	logs, err := c.FilterLogs(ctx, buildQuery(contractAddress, methodSig, startBlock, endBlock))
+	return c.FetchIndexedEventLogs(ctx, contractAddress, methodSig, nil, startBlock, endBlock)
+}
+
+// FetchIndexedEventLogs는 methodSig 이벤트 로그 중 indexed 인자가 topics와 일치하는 로그를 조회합니다.
+// topics의 i 번째 값은 i 번째 indexed 인자의 후보 목록이며, 비어있다면 모든 값과 일치합니다.
+func (c *EvmClient) FetchIndexedEventLogs(ctx context.Context, contractAddress common.Address, methodSig message.EventSig, topics [][]common.Hash, startBlock *big.Int, endBlock *big.Int) ([]types.Log, error) {
+	logs, err := c.FilterLogs(ctx, buildQuery(contractAddress, methodSig, startBlock, endBlock, topics...))
 	if err != nil {
 		return []types.Log{}, err
 	}
@@ -202,6 +307,69 @@ func (c *EvmClient) FetchEventLogs(ctx context.Context, contractAddress common.A
 	return validLogs, nil
 }
 
+// rangeLimitMessages는 RPC가 eth_getLogs 요청의 블록 범위나 결과 크기를 거부할 때 반환하는 에러 메시지입니다.
+// 요청 수 제한 에러와 구분되지 않는 "limit exceeded" 같은 일반적인 문구는 포함하지 않습니다.
+var rangeLimitMessages = []string{
+	"block range",
+	"query returned more than",
+	"too many blocks",
+	"range too large",
+	"range is too large",
+	"exceed maximum block range",
+	"response size exceeded",
+}
+
+// IsRangeLimitError는 err가 eth_getLogs 요청의 범위 제한으로 인한 에러인지 확인합니다.
+func IsRangeLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range rangeLimitMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscribeEventLogs는 컨트랙트에서 발생하는 methodSig 이벤트 로그를 구독합니다.
+// websocket과 같이 구독을 지원하는 endpoint에서만 사용할 수 있습니다.
+func (c *EvmClient) SubscribeEventLogs(ctx context.Context, contractAddress common.Address, methodSig message.EventSig, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if c.subscriber == nil {
+		return nil, errors.New("subscription requires websocket endpoint")
+	}
+	return c.subscriber.SubscribeFilterLogs(ctx, buildQuery(contractAddress, methodSig, nil, nil), ch)
+}
+
+// SubscribeNewHead는 새로운 블록 헤더를 구독합니다.
+func (c *EvmClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	if c.subscriber == nil {
+		return nil, errors.New("subscription requires websocket endpoint")
+	}
+	return c.subscriber.SubscribeNewHead(ctx, ch)
+}
+
+// IsWebsocket은 이벤트 구독에 사용할 websocket 연결이 있는지 확인합니다.
+func (c *EvmClient) IsWebsocket() bool {
+	return c.subscriber != nil
+}
+
+// Close는 RPC 연결과 endpoint 상태 점검을 종료합니다.
+func (c *EvmClient) Close() {
+	if c.pool != nil {
+		c.pool.close()
+	}
+	if c.subscriber != nil && c.subscriber != c.Client {
+		c.subscriber.Close()
+	}
+	c.Client.Close()
+}
+
+func isWebsocket(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "ws://") || strings.HasPrefix(endpoint, "wss://")
+}
+
 // SendRawTransaction accepts rlp-encode of signed transaction and sends it via RPC call
 func (c *EvmClient) SendRawTransaction(ctx context.Context, tx []byte) ([]byte, error) {
 	var hex hexutil.Bytes
@@ -240,16 +408,27 @@ func (c *EvmClient) From() common.Address {
 }
 
 func (c *EvmClient) SignAndSendTransaction(ctx context.Context, tx transaction.CommonTransaction) (common.Hash, error) {
+	rawTx, err := c.SignTransaction(ctx, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return c.SendSignedTransaction(ctx, tx, rawTx)
+}
+
+// SignTransaction은 트랜잭션을 서명하고 rlp-encode된 결과를 반환합니다.
+// 서명 이후 tx.Hash()는 전송될 트랜잭션의 hash를 반환합니다.
+func (c *EvmClient) SignTransaction(ctx context.Context, tx transaction.CommonTransaction) ([]byte, error) {
 	id, err := c.ChainID(ctx)
 	if err != nil {
 		// panic(err)
 		// Probably chain does not support chainID eg. CELO
 		id = nil
 	}
-	rawTx, err := tx.RawWithSignature(c.signer, id)
-	if err != nil {
-		return common.Hash{}, err
-	}
+	return tx.RawWithSignature(c.signer, id)
+}
+
+// SendSignedTransaction은 SignTransaction으로 서명된 트랜잭션을 전송합니다.
+func (c *EvmClient) SendSignedTransaction(ctx context.Context, tx transaction.CommonTransaction, rawTx []byte) (common.Hash, error) {
 	hex, err := c.SendRawTransaction(ctx, rawTx)
 	if err != nil {
 		return common.Hash{}, err
@@ -285,14 +464,14 @@ func (c *EvmClient) EnsureHasBytecode(addr common.Address) error {
 	return nil
 }
 
-func buildQuery(contract common.Address, sig message.EventSig, startBlock *big.Int, endBlock *big.Int) ethereum.FilterQuery {
+func buildQuery(contract common.Address, sig message.EventSig, startBlock *big.Int, endBlock *big.Int, topics ...[]common.Hash) ethereum.FilterQuery {
 	query := ethereum.FilterQuery{
 		FromBlock: startBlock,
 		ToBlock:   endBlock,
 		Addresses: []common.Address{contract},
-		Topics: [][]common.Hash{
+		Topics: append([][]common.Hash{
 			{sig.GetTopic()},
-		},
+		}, topics...),
 	}
 	return query
 }
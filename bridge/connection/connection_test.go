@@ -44,6 +44,27 @@ func TestBuildQuery(t *testing.T) {
 	t.Log(event)
 }
 
+// 범위 제한 에러만 블록 범위를 줄일 대상으로 분류하는가?
+func TestIsRangeLimitError(t *testing.T) {
+	testCases := []struct {
+		err    error
+		expect bool
+	}{
+		{errors.New("query returned more than 10000 results"), true},
+		{errors.New("block range is too wide"), true},
+		{errors.New("exceed maximum block range: 5000"), true},
+		{errors.New("Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"), true},
+		{errors.New("dial tcp: connection refused"), false},
+		{errors.New("429 Too Many Requests: rate limit exceeded"), false},
+		{errors.New("daily request limit exceeded"), false},
+		{errors.New("invalid argument 0: hex string without 0x prefix"), false},
+		{nil, false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expect, IsRangeLimitError(tc.err), "%v", tc.err)
+	}
+}
+
 func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
 	arg := map[string]interface{}{
 		"address": q.Addresses,
@@ -0,0 +1,253 @@
+package connection
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+)
+
+var (
+	HealthCheckInterval    = 15 * time.Second
+	EndpointRequestTimeout = 30 * time.Second
+	MaxHeadLag             = uint64(10)       // 가장 높은 head와의 허용 블록 차이
+	MaxHeadAge             = 10 * time.Minute // 최신 블록 생성 이후 허용 시간
+)
+
+// endpointState는 RPC endpoint 하나의 상태를 기록합니다.
+type endpointState struct {
+	raw     string
+	url     *url.URL
+	client  *rpc.Client // health check 용 client
+	healthy bool
+	head    uint64
+	reason  string
+}
+
+// endpointPool은 여러 HTTP RPC endpoint의 상태를 점검하고 가장 건강한 endpoint로 요청을 전달하는 http.RoundTripper입니다.
+// 요청이 실패하면 다음 endpoint로 같은 요청을 다시 전송합니다.
+type endpointPool struct {
+	endpoints []*endpointState
+	chainId   *big.Int
+	transport http.RoundTripper
+	lock      sync.RWMutex
+	stop      chan struct{}
+	logger    *zerolog.Logger
+}
+
+func newEndpointPool(endpoints []string, logger *zerolog.Logger) (*endpointPool, error) {
+	pool := &endpointPool{
+		transport: http.DefaultTransport,
+		stop:      make(chan struct{}),
+		logger:    logger,
+	}
+	for _, raw := range endpoints {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint url:%s, err:%w", raw, err)
+		}
+		client, err := rpc.DialHTTP(raw)
+		if err != nil {
+			return nil, fmt.Errorf("cannot dial to rpc node. url:%s, err:%w", raw, err)
+		}
+		pool.endpoints = append(pool.endpoints, &endpointState{raw: raw, url: u, client: client, healthy: true})
+	}
+	return pool, nil
+}
+
+// RoundTrip은 건강한 endpoint부터 순서대로 요청을 전송합니다.
+func (p *endpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	var lastErr error
+	for _, ep := range p.candidates() {
+		ctx, cancel := context.WithTimeout(req.Context(), EndpointRequestTimeout)
+		r := req.Clone(ctx)
+		r.URL = ep.url
+		r.Host = ep.url.Host
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+
+		resp, err := p.transport.RoundTrip(r)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+		cancel()
+
+		p.markUnhealthy(ep, err.Error())
+		lastErr = err
+		if req.Context().Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// candidates는 요청을 전송할 endpoint 순서를 반환합니다.
+// 건강한 endpoint를 head가 높은 순서로 먼저 반환하고, 나머지 endpoint는 최후의 수단으로 뒤에 붙입니다.
+func (p *endpointPool) candidates() []*endpointState {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	healthy := make([]*endpointState, 0, len(p.endpoints))
+	unhealthy := make([]*endpointState, 0)
+	for _, ep := range p.endpoints {
+		if ep.healthy {
+			healthy = append(healthy, ep)
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+	sort.SliceStable(healthy, func(i, j int) bool { return healthy[i].head > healthy[j].head })
+	return append(healthy, unhealthy...)
+}
+
+func (p *endpointPool) markUnhealthy(ep *endpointState, reason string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if ep.healthy {
+		p.logger.Warn().Msgf("rpc endpoint became unhealthy. url:%s, reason:%s", ep.raw, reason)
+	}
+	ep.healthy = false
+	ep.reason = reason
+}
+
+// start는 주기적으로 endpoint의 상태를 점검합니다.
+func (p *endpointPool) start(chainId *big.Int) {
+	p.lock.Lock()
+	p.chainId = chainId
+	p.lock.Unlock()
+
+	p.checkHealth()
+	go func() {
+		ticker := time.NewTicker(HealthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.checkHealth()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// checkHealth는 각 endpoint의 chain id와 최신 블록을 확인합니다.
+// chain id가 다르거나, 응답하지 않거나, 최신 블록이 오래되었거나 다른 endpoint보다 뒤처진 endpoint는 건강하지 않은 것으로 표시합니다.
+func (p *endpointPool) checkHealth() {
+	type result struct {
+		head   uint64
+		reason string
+	}
+	results := make([]result, len(p.endpoints))
+
+	var wg sync.WaitGroup
+	for i, ep := range p.endpoints {
+		wg.Add(1)
+		go func(i int, ep *endpointState) {
+			defer wg.Done()
+			head, err := p.probe(ep)
+			if err != nil {
+				results[i] = result{reason: err.Error()}
+				return
+			}
+			results[i] = result{head: head}
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var best uint64
+	for _, r := range results {
+		if r.reason == "" && r.head > best {
+			best = r.head
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for i, ep := range p.endpoints {
+		r := results[i]
+		if r.reason == "" && r.head+MaxHeadLag < best {
+			r.reason = fmt.Sprintf("head is behind. head:%d, best:%d", r.head, best)
+		}
+
+		healthy := r.reason == ""
+		if healthy && !ep.healthy {
+			p.logger.Info().Msgf("rpc endpoint recovered. url:%s, head:%d", ep.raw, r.head)
+		} else if !healthy && ep.healthy {
+			p.logger.Warn().Msgf("rpc endpoint became unhealthy. url:%s, reason:%s", ep.raw, r.reason)
+		}
+		ep.healthy = healthy
+		ep.head = r.head
+		ep.reason = r.reason
+	}
+}
+
+// probe는 endpoint의 chain id와 최신 블록을 조회합니다.
+func (p *endpointPool) probe(ep *endpointState) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), EndpointRequestTimeout)
+	defer cancel()
+
+	chainId := new(hexutil.Big)
+	if err := ep.client.CallContext(ctx, chainId, "eth_chainId"); err != nil {
+		return 0, fmt.Errorf("cannot get chain id: %w", err)
+	}
+	if p.chainId != nil && (*big.Int)(chainId).Cmp(p.chainId) != 0 {
+		return 0, fmt.Errorf("chain id mismatch. expected:%s, got:%s", p.chainId.String(), (*big.Int)(chainId).String())
+	}
+
+	var head struct {
+		Number    hexutil.Uint64 `json:"number"`
+		Timestamp hexutil.Uint64 `json:"timestamp"`
+	}
+	if err := ep.client.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return 0, fmt.Errorf("cannot get latest block: %w", err)
+	}
+	if age := time.Since(time.Unix(int64(head.Timestamp), 0)); age > MaxHeadAge {
+		return 0, fmt.Errorf("head is stale. head:%d, age:%s", uint64(head.Number), age.Round(time.Second))
+	}
+	return uint64(head.Number), nil
+}
+
+func (p *endpointPool) close() {
+	close(p.stop)
+	for _, ep := range p.endpoints {
+		ep.client.Close()
+	}
+}
+
+// cancelBody는 응답 body를 모두 읽은 뒤 요청 context를 취소합니다.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
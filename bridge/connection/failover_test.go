@@ -0,0 +1,96 @@
+package connection
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRPCServer는 eth_chainId와 eth_getBlockByNumber에 응답하는 테스트 RPC 서버를 생성합니다.
+func newTestRPCServer(t *testing.T, chainId, head uint64, fail bool) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		var result interface{}
+		switch req.Method {
+		case "eth_chainId":
+			result = hexutil.Uint64(chainId)
+		case "eth_getBlockByNumber":
+			result = map[string]interface{}{
+				"number":    hexutil.Uint64(head),
+				"timestamp": hexutil.Uint64(time.Now().Unix()),
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  result,
+		}))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// 요청에 실패한 endpoint를 건너뛰고 다른 endpoint로 요청을 전달하는가?
+func TestEndpointPoolFailover(t *testing.T) {
+	logger := zerolog.Nop()
+	broken := newTestRPCServer(t, 1, 100, true)
+	healthy := newTestRPCServer(t, 1, 100, false)
+
+	pool, err := newEndpointPool([]string{broken.URL, healthy.URL}, &logger)
+	require.NoError(t, err)
+	defer pool.close()
+
+	client, err := rpc.DialHTTPWithClient(broken.URL, &http.Client{Transport: pool})
+	require.NoError(t, err)
+
+	chainId := new(hexutil.Big)
+	err = client.CallContext(context.Background(), chainId, "eth_chainId")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), (*big.Int)(chainId).Int64())
+
+	candidates := pool.candidates()
+	require.Equal(t, healthy.URL, candidates[0].raw)
+	require.False(t, candidates[1].healthy)
+}
+
+// chain id가 다르거나 head가 뒤처진 endpoint를 건강하지 않은 것으로 표시하는가?
+func TestEndpointPoolHealthCheck(t *testing.T) {
+	logger := zerolog.Nop()
+	best := newTestRPCServer(t, 1, 100, false)
+	otherChain := newTestRPCServer(t, 2, 100, false)
+	lagging := newTestRPCServer(t, 1, 100-MaxHeadLag-1, false)
+
+	pool, err := newEndpointPool([]string{lagging.URL, otherChain.URL, best.URL}, &logger)
+	require.NoError(t, err)
+	defer pool.close()
+
+	pool.start(big.NewInt(1))
+
+	states := map[string]*endpointState{}
+	for _, ep := range pool.candidates() {
+		states[ep.raw] = ep
+	}
+	require.True(t, states[best.URL].healthy)
+	require.False(t, states[otherChain.URL].healthy)
+	require.False(t, states[lagging.URL].healthy)
+	require.Equal(t, best.URL, pool.candidates()[0].raw)
+}
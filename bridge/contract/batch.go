@@ -0,0 +1,48 @@
+package contract
+
+import (
+	"berith-swap/bridge/contract/consts"
+	"berith-swap/bridge/transaction"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog"
+)
+
+// BatchTransferContract는 여러 수신자에게 한 번의 트랜잭션으로 토큰을 전송하는 컨트랙트입니다.
+// 컨트랙트 owner의 토큰을 transferFrom으로 전송하므로 owner가 컨트랙트에 allowance를 부여해야 합니다.
+type BatchTransferContract struct {
+	Contract
+	Logger *zerolog.Logger
+}
+
+func NewBatchTransferContract(
+	client transaction.ContractCallerDispatcher,
+	batchContractAddress common.Address,
+	transactor transaction.Transactor,
+	logger *zerolog.Logger,
+) *BatchTransferContract {
+	a, _ := abi.JSON(strings.NewReader(consts.BatchTransferABI))
+	return &BatchTransferContract{
+		Contract: NewContract(batchContractAddress, a, nil, client, transactor, logger),
+		Logger:   logger,
+	}
+}
+
+func (c *BatchTransferContract) WaitAndReturnTxReceipt(hash *common.Hash) (*types.Receipt, error) {
+	return c.Contract.client.WaitAndReturnTxReceipt(*hash)
+}
+
+// SubmitBatchTransfer는 recipients에게 각각 amounts 만큼의 token을 전송하는 트랜잭션을 전송합니다.
+func (c *BatchTransferContract) SubmitBatchTransfer(
+	token common.Address,
+	recipients []common.Address,
+	amounts []*big.Int,
+	opts transaction.TransactOptions,
+) (*common.Hash, error) {
+	c.Logger.Debug().Msgf("submit batch transfer of %s to %d recipients", token.Hex(), len(recipients))
+	return c.SubmitTransaction("batchTransfer", opts, token, recipients, amounts)
+}
@@ -0,0 +1,33 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// batchTransfer 호출 데이터가 ABI에 맞게 인코딩되는가?
+func TestPackBatchTransfer(t *testing.T) {
+	logger := zerolog.Nop()
+	batchCtr := NewBatchTransferContract(nil, common.HexToAddress("0x01"), nil, &logger)
+
+	token := common.HexToAddress("0x02")
+	recipients := []common.Address{common.HexToAddress("0x03"), common.HexToAddress("0x04")}
+	amounts := []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	input, err := batchCtr.PackMethod("batchTransfer", token, recipients, amounts)
+	require.NoError(t, err)
+
+	method, err := batchCtr.ABI.MethodById(input[:4])
+	require.NoError(t, err)
+	require.Equal(t, "batchTransfer", method.Name)
+
+	args, err := method.Inputs.Unpack(input[4:])
+	require.NoError(t, err)
+	require.Equal(t, token, args[0])
+	require.Equal(t, recipients, args[1])
+	require.Equal(t, amounts, args[2])
+}
@@ -80,6 +80,27 @@ func (c *Contract) ExecuteTransaction(method string, opts transaction.TransactOp
 	return h, err
 }
 
+// SubmitTransaction은 method 호출 트랜잭션을 전송하고 receipt를 기다리지 않고 반환합니다.
+func (c *Contract) SubmitTransaction(method string, opts transaction.TransactOptions, args ...interface{}) (*common.Hash, error) {
+	input, err := c.PackMethod(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	h, err := c.Submit(&c.contractAddress, input, opts)
+	if err != nil {
+		c.Logger.Error().
+			Str("contract", c.contractAddress.String()).
+			Err(err).
+			Msgf("error on submitting %s", method)
+		return nil, err
+	}
+	c.Logger.Debug().
+		Str("txHash", h.String()).
+		Str("contract", c.contractAddress.String()).
+		Msgf("method %s submitted", method)
+	return h, err
+}
+
 func (c *Contract) CallContract(method string, args ...interface{}) ([]interface{}, error) {
 	input, err := c.PackMethod(method, args...)
 	if err != nil {
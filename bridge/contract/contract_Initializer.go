@@ -50,3 +50,21 @@ func IniBridgeContract(c *connection.EvmClient, bridgeAddr string, logger *zerol
 	}
 	return NewSwapContract(c, common.HexToAddress(bridgeAddr), t, logger), nil
 }
+
+func InitBatchTransferContract(c *connection.EvmClient, batchAddr string, logger *zerolog.Logger) (*BatchTransferContract, error) {
+
+	t, err := InitializeTransactor(KlaytnBaseFee, transaction.NewTransaction, c)
+	if err != nil {
+		return nil, err
+	}
+	return NewBatchTransferContract(c, common.HexToAddress(batchAddr), t, logger), nil
+}
+
+func InitMintableTokenContract(c *connection.EvmClient, tokenAddr string, logger *zerolog.Logger) (*MintableTokenContract, error) {
+
+	t, err := InitializeTransactor(KlaytnBaseFee, transaction.NewTransaction, c)
+	if err != nil {
+		return nil, err
+	}
+	return NewMintableTokenContract(c, common.HexToAddress(tokenAddr), t, logger), nil
+}
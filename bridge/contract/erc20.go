@@ -35,6 +35,15 @@ func (c *ERC20Contract) WaitAndReturnTxReceipt(hash *common.Hash) (*types.Receip
 	return c.Contract.client.WaitAndReturnTxReceipt(*hash)
 }
 
+// ParseTransferValue는 Transfer 이벤트 로그에서 전송된 토큰 양을 읽어옵니다.
+func (c *ERC20Contract) ParseTransferValue(log types.Log) (*big.Int, error) {
+	res, err := c.UnpackResult("Transfer", log.Data)
+	if err != nil {
+		return nil, err
+	}
+	return abi.ConvertType(res[0], new(big.Int)).(*big.Int), nil
+}
+
 func (c *ERC20Contract) GetBalance(address common.Address) (*big.Int, error) {
 	c.Logger.Debug().Msgf("Getting balance for %s", address.String())
 	res, err := c.CallContract("balanceOf", address)
@@ -45,6 +54,17 @@ func (c *ERC20Contract) GetBalance(address common.Address) (*big.Int, error) {
 	return b, nil
 }
 
+// GetAllowance는 owner가 spender에게 허용한 토큰 양을 조회합니다.
+func (c *ERC20Contract) GetAllowance(owner, spender common.Address) (*big.Int, error) {
+	c.Logger.Debug().Msgf("Getting allowance of %s for %s", owner.String(), spender.String())
+	res, err := c.CallContract("allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	b := abi.ConvertType(res[0], new(big.Int)).(*big.Int)
+	return b, nil
+}
+
 func (c *ERC20Contract) GetPauseState() (*bool, error) {
 	c.Logger.Debug().Msg("Getting pause state")
 	res, err := c.CallContract("paused")
@@ -69,6 +89,16 @@ func (c *ERC20Contract) UnPause(
 	return c.ExecuteTransaction("unpause", opts)
 }
 
+// Approve는 spender가 amount 만큼의 토큰을 전송할 수 있도록 허용합니다.
+func (c *ERC20Contract) Approve(
+	spender common.Address,
+	amount *big.Int,
+	opts transaction.TransactOptions,
+) (*common.Hash, error) {
+	c.Logger.Debug().Msgf("approve %s tokens to %s", amount.String(), spender.String())
+	return c.ExecuteTransaction("approve", opts, spender, amount)
+}
+
 func (c *ERC20Contract) Transfer(
 	to common.Address,
 	amount *big.Int,
@@ -77,3 +107,13 @@ func (c *ERC20Contract) Transfer(
 	c.Logger.Debug().Msgf("transfer %s tokens to %s", amount.String(), to.String())
 	return c.ExecuteTransaction("transfer", opts, to, amount)
 }
+
+// SubmitTransfer는 토큰 전송 트랜잭션을 전송하고 receipt를 기다리지 않고 반환합니다.
+func (c *ERC20Contract) SubmitTransfer(
+	to common.Address,
+	amount *big.Int,
+	opts transaction.TransactOptions,
+) (*common.Hash, error) {
+	c.Logger.Debug().Msgf("submit transfer %s tokens to %s", amount.String(), to.String())
+	return c.SubmitTransaction("transfer", opts, to, amount)
+}
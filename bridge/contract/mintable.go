@@ -0,0 +1,57 @@
+package contract
+
+import (
+	"berith-swap/bridge/contract/consts"
+	"berith-swap/bridge/transaction"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+)
+
+// MintableTokenContract는 minter 권한을 가진 계정이 mint(to, amount)로 토큰을 발행하는 토큰 컨트랙트입니다.
+type MintableTokenContract struct {
+	Contract
+	Logger *zerolog.Logger
+}
+
+func NewMintableTokenContract(
+	client transaction.ContractCallerDispatcher,
+	tokenAddress common.Address,
+	transactor transaction.Transactor,
+	logger *zerolog.Logger,
+) *MintableTokenContract {
+	a, _ := abi.JSON(strings.NewReader(consts.MintableTokenABI))
+	return &MintableTokenContract{
+		Contract: NewContract(tokenAddress, a, nil, client, transactor, logger),
+		Logger:   logger,
+	}
+}
+
+// HasMinterRole은 account가 토큰을 mint 할 수 있는 MINTER_ROLE을 가지고 있는지 조회합니다.
+func (c *MintableTokenContract) HasMinterRole(account common.Address) (bool, error) {
+	c.Logger.Debug().Msgf("Getting minter role of %s", account.String())
+	res, err := c.CallContract("MINTER_ROLE")
+	if err != nil {
+		return false, err
+	}
+	role := abi.ConvertType(res[0], new([32]byte)).(*[32]byte)
+
+	res, err = c.CallContract("hasRole", *role, account)
+	if err != nil {
+		return false, err
+	}
+	return *abi.ConvertType(res[0], new(bool)).(*bool), nil
+}
+
+// SubmitMint는 to에게 amount 만큼의 토큰을 mint 하는 트랜잭션을 전송하고 receipt를 기다리지 않고 반환합니다.
+func (c *MintableTokenContract) SubmitMint(
+	to common.Address,
+	amount *big.Int,
+	opts transaction.TransactOptions,
+) (*common.Hash, error) {
+	c.Logger.Debug().Msgf("submit mint %s tokens to %s", amount.String(), to.String())
+	return c.SubmitTransaction("mint", opts, to, amount)
+}
@@ -0,0 +1,32 @@
+package contract
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// mint 호출 데이터가 ABI에 맞게 인코딩되는가?
+func TestPackMint(t *testing.T) {
+	logger := zerolog.Nop()
+	tokenCtr := NewMintableTokenContract(nil, common.HexToAddress("0x01"), nil, &logger)
+
+	to := common.HexToAddress("0x02")
+	amount := big.NewInt(100)
+
+	input, err := tokenCtr.PackMethod("mint", to, amount)
+	require.NoError(t, err)
+
+	method, err := tokenCtr.ABI.MethodById(input[:4])
+	require.NoError(t, err)
+	require.Equal(t, "mint", method.Name)
+	require.Equal(t, common.FromHex("0x40c10f19"), input[:4])
+
+	args, err := method.Inputs.Unpack(input[4:])
+	require.NoError(t, err)
+	require.Equal(t, to, args[0])
+	require.Equal(t, amount, args[1])
+}
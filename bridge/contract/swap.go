@@ -42,6 +42,16 @@ func (b *SwapContract) Deposit(receiver common.Address,
 	return b.ExecuteTransaction("deposit", opts, receiver)
 }
 
+// ParseDepositNonce는 Deposit 이벤트 로그에서 depositNonce를 읽어옵니다.
+func (b *SwapContract) ParseDepositNonce(log types.Log) (uint64, error) {
+	res, err := b.UnpackResult("Deposit", log.Data)
+	if err != nil {
+		return 0, err
+	}
+	nonce := abi.ConvertType(res[0], new(uint64)).(*uint64)
+	return *nonce, nil
+}
+
 func (b *SwapContract) GetBalance(address common.Address) (*big.Int, error) {
 	b.Logger.Debug().Msgf("Getting balance for %s", address.String())
 	res, err := b.CallContract("balanceOf", address)
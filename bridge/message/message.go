@@ -7,13 +7,15 @@ import (
 )
 
 type DepositMessage struct {
-	BlockNumber  uint64         `validate:"required"`
+	BlockNumber  uint64 `validate:"required"`
+	BlockHash    common.Hash
+	DepositNonce uint64
 	Sender       common.Address `validate:"required"`
 	Receiver     common.Address `validate:"required"`
 	Amount       *big.Int       `validate:"required"`
 	SenderTxHash string         `validate:"required,len=66"`
 }
 
-func NewDepositMessage(blockNumber uint64, sender, receiver common.Address, amount *big.Int, hash string) DepositMessage {
-	return DepositMessage{BlockNumber: blockNumber, Sender: sender, Receiver: receiver, Amount: amount, SenderTxHash: hash}
+func NewDepositMessage(blockNumber uint64, blockHash common.Hash, depositNonce uint64, sender, receiver common.Address, amount *big.Int, hash string) DepositMessage {
+	return DepositMessage{BlockNumber: blockNumber, BlockHash: blockHash, DepositNonce: depositNonce, Sender: sender, Receiver: receiver, Amount: amount, SenderTxHash: hash}
 }
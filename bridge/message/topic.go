@@ -12,5 +12,8 @@ func (es EventSig) GetTopic() common.Hash {
 }
 
 const (
-	Deposit EventSig = "Deposit(uint64,address)"
+	Deposit  EventSig = "Deposit(uint64,address)"
+	Paused   EventSig = "Paused(address)"
+	Unpaused EventSig = "Unpaused(address)"
+	Transfer EventSig = "Transfer(address,address,uint256)"
 )
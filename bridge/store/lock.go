@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrLockHeld는 다른 프로세스가 같은 이름의 lock을 가지고 있을 때 반환됩니다.
+var ErrLockHeld = errors.New("lock is held by another process")
+
+// Lock은 DB의 named lock입니다.
+// named lock은 DB 연결에 묶여 있으므로 Release를 호출할 때까지 연결을 유지합니다.
+type Lock struct {
+	name string
+	conn *sql.Conn
+}
+
+// AcquireLock은 name의 named lock을 기다리지 않고 가져옵니다.
+// 다른 연결이 lock을 가지고 있다면 ErrLockHeld를 반환합니다.
+func (s *Store) AcquireLock(ctx context.Context, name string) (*Lock, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var locked sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", name).Scan(&locked)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("%w. name:%s", ErrLockHeld, name)
+	}
+	return &Lock{name: name, conn: conn}, nil
+}
+
+// Release는 named lock을 해제하고 DB 연결을 반환합니다.
+func (l *Lock) Release() error {
+	_, err := l.conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", l.name)
+	if closeErr := l.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
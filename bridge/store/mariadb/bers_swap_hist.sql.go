@@ -15,9 +15,14 @@ INSERT INTO bers_swap_hist(
     sender_tx_hash,
     receiver_tx_hash,
     amount,
-    berith_address
+    berith_address,
+    deposit_amount,
+    gross_amount,
+    fee_amount,
+    payout_amount,
+    direction
 ) VALUES (
-    ?,?,?,?
+    ?,?,?,?,?,?,?,?,?
 )
 `
 
@@ -26,6 +31,11 @@ type CreateBersSwapHistoryParams struct {
 	ReceiverTxHash string `json:"receiver_tx_hash"`
 	Amount         int64  `json:"amount"`
 	BerithAddress  string `json:"berith_address"`
+	DepositAmount  string `json:"deposit_amount"`
+	GrossAmount    string `json:"gross_amount"`
+	FeeAmount      string `json:"fee_amount"`
+	PayoutAmount   string `json:"payout_amount"`
+	Direction      string `json:"direction"`
 }
 
 func (q *Queries) CreateBersSwapHistory(ctx context.Context, arg CreateBersSwapHistoryParams) (sql.Result, error) {
@@ -34,11 +44,16 @@ func (q *Queries) CreateBersSwapHistory(ctx context.Context, arg CreateBersSwapH
 		arg.ReceiverTxHash,
 		arg.Amount,
 		arg.BerithAddress,
+		arg.DepositAmount,
+		arg.GrossAmount,
+		arg.FeeAmount,
+		arg.PayoutAmount,
+		arg.Direction,
 	)
 }
 
 const getBersSwapHistory = `-- name: GetBersSwapHistory :one
-SELECT sender_tx_hash, receiver_tx_hash, berith_address, amount, created_at FROM bers_swap_hist
+SELECT sender_tx_hash, receiver_tx_hash, berith_address, amount, created_at, deposit_amount, payout_amount, gross_amount, fee_amount, direction FROM bers_swap_hist
 WHERE sender_tx_hash = ?
 `
 
@@ -51,12 +66,17 @@ func (q *Queries) GetBersSwapHistory(ctx context.Context, senderTxHash string) (
 		&i.BerithAddress,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.DepositAmount,
+		&i.PayoutAmount,
+		&i.GrossAmount,
+		&i.FeeAmount,
+		&i.Direction,
 	)
 	return i, err
 }
 
 const getSwapHistByBerithAddress = `-- name: GetSwapHistByBerithAddress :many
-SELECT sender_tx_hash, receiver_tx_hash, berith_address, amount, created_at FROM bers_swap_hist
+SELECT sender_tx_hash, receiver_tx_hash, berith_address, amount, created_at, deposit_amount, payout_amount, gross_amount, fee_amount, direction FROM bers_swap_hist
 WHERE berith_address = ?
 `
 
@@ -75,6 +95,11 @@ func (q *Queries) GetSwapHistByBerithAddress(ctx context.Context, berithAddress
 			&i.BerithAddress,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.DepositAmount,
+			&i.PayoutAmount,
+			&i.GrossAmount,
+			&i.FeeAmount,
+			&i.Direction,
 		); err != nil {
 			return nil, err
 		}
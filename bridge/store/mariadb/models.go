@@ -8,10 +8,78 @@ import (
 	"database/sql"
 )
 
+type BersDeadLetter struct {
+	SenderTxHash    string         `json:"sender_tx_hash"`
+	BlockNumber     int64          `json:"block_number"`
+	SenderAddress   string         `json:"sender_address"`
+	ReceiverAddress string         `json:"receiver_address"`
+	Amount          string         `json:"amount"`
+	RetryCount      int32          `json:"retry_count"`
+	LastError       sql.NullString `json:"last_error"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+}
+
+type BersDepositQueue struct {
+	SenderTxHash    string         `json:"sender_tx_hash"`
+	BlockNumber     int64          `json:"block_number"`
+	BlockHash       string         `json:"block_hash"`
+	DepositNonce    int64          `json:"deposit_nonce"`
+	SenderAddress   string         `json:"sender_address"`
+	ReceiverAddress string         `json:"receiver_address"`
+	Amount          string         `json:"amount"`
+	Status          string         `json:"status"`
+	CreatedAt       sql.NullTime   `json:"created_at"`
+	UpdatedAt       sql.NullTime   `json:"updated_at"`
+	ReceiverTxHash  sql.NullString `json:"receiver_tx_hash"`
+	ReceiverNonce   sql.NullInt64  `json:"receiver_nonce"`
+	Reason          sql.NullString `json:"reason"`
+	RetryCount      int32          `json:"retry_count"`
+	NextRetryAt     sql.NullTime   `json:"next_retry_at"`
+	LastError       sql.NullString `json:"last_error"`
+	ApprovedAt      sql.NullTime   `json:"approved_at"`
+	Direction       string         `json:"direction"`
+	GrossAmount     sql.NullString `json:"gross_amount"`
+	FeeAmount       sql.NullString `json:"fee_amount"`
+	PayoutAmount    sql.NullString `json:"payout_amount"`
+}
+
+type BersFeeSweep struct {
+	TxHash          string        `json:"tx_hash"`
+	TreasuryAddress string        `json:"treasury_address"`
+	Amount          string        `json:"amount"`
+	Status          string        `json:"status"`
+	CreatedAt       sql.NullTime  `json:"created_at"`
+	UpdatedAt       sql.NullTime  `json:"updated_at"`
+	Nonce           sql.NullInt64 `json:"nonce"`
+}
+
+type BersRefund struct {
+	SenderTxHash  string       `json:"sender_tx_hash"`
+	TxHash        string       `json:"tx_hash"`
+	Nonce         int64        `json:"nonce"`
+	SenderAddress string       `json:"sender_address"`
+	Amount        string       `json:"amount"`
+	DepositStatus string       `json:"deposit_status"`
+	Status        string       `json:"status"`
+	CreatedAt     sql.NullTime `json:"created_at"`
+	UpdatedAt     sql.NullTime `json:"updated_at"`
+}
+
 type BersSwapHist struct {
 	SenderTxHash   string       `json:"sender_tx_hash"`
 	ReceiverTxHash string       `json:"receiver_tx_hash"`
 	BerithAddress  string       `json:"berith_address"`
 	Amount         int64        `json:"amount"`
 	CreatedAt      sql.NullTime `json:"created_at"`
+	DepositAmount  string       `json:"deposit_amount"`
+	PayoutAmount   string       `json:"payout_amount"`
+	GrossAmount    string       `json:"gross_amount"`
+	FeeAmount      string       `json:"fee_amount"`
+	Direction      string       `json:"direction"`
+}
+
+type BersSwapReview struct {
+	SenderTxHash string       `json:"sender_tx_hash"`
+	Reason       string       `json:"reason"`
+	CreatedAt    sql.NullTime `json:"created_at"`
 }
@@ -10,9 +10,46 @@ import (
 )
 
 type Querier interface {
+	ApproveDeposit(ctx context.Context, senderTxHash string) (sql.Result, error)
 	CreateBersSwapHistory(ctx context.Context, arg CreateBersSwapHistoryParams) (sql.Result, error)
+	CreateDeadLetter(ctx context.Context, senderTxHash string) (sql.Result, error)
+	CreateFeeSweep(ctx context.Context, arg CreateFeeSweepParams) (sql.Result, error)
+	CreateRefund(ctx context.Context, arg CreateRefundParams) error
+	CreateSwapReview(ctx context.Context, arg CreateSwapReviewParams) (sql.Result, error)
+	DeleteDeadLetter(ctx context.Context, senderTxHash string) (sql.Result, error)
+	EnqueueDeposit(ctx context.Context, arg EnqueueDepositParams) (sql.Result, error)
+	FailDeposit(ctx context.Context, arg FailDepositParams) error
 	GetBersSwapHistory(ctx context.Context, senderTxHash string) (BersSwapHist, error)
+	GetDeadLetter(ctx context.Context, senderTxHash string) (BersDeadLetter, error)
+	GetDeposit(ctx context.Context, senderTxHash string) (BersDepositQueue, error)
+	GetDepositVolume(ctx context.Context, direction string) (string, error)
+	GetDepositVolumeBySender(ctx context.Context, arg GetDepositVolumeBySenderParams) (string, error)
+	GetFeeBalance(ctx context.Context) (string, error)
+	GetRefund(ctx context.Context, senderTxHash string) (BersRefund, error)
 	GetSwapHistByBerithAddress(ctx context.Context, berithAddress string) ([]BersSwapHist, error)
+	GetSwapReview(ctx context.Context, senderTxHash string) (BersSwapReview, error)
+	HoldDeposit(ctx context.Context, arg HoldDepositParams) error
+	HoldSubmittedDeposit(ctx context.Context, arg HoldSubmittedDepositParams) error
+	ListDeadLetters(ctx context.Context) ([]BersDeadLetter, error)
+	ListDepositsByStatus(ctx context.Context, arg ListDepositsByStatusParams) ([]BersDepositQueue, error)
+	ListFeeSweeps(ctx context.Context) ([]BersFeeSweep, error)
+	ListFeeSweepsByStatus(ctx context.Context, status string) ([]BersFeeSweep, error)
+	ListPendingDeposits(ctx context.Context, arg ListPendingDepositsParams) ([]BersDepositQueue, error)
+	ListRefundableDeposits(ctx context.Context, arg ListRefundableDepositsParams) ([]BersDepositQueue, error)
+	ListRefundsByStatus(ctx context.Context, status string) ([]BersRefund, error)
+	ListSwapReviews(ctx context.Context) ([]BersSwapReview, error)
+	LockSubmittedFeeSweeps(ctx context.Context) ([]string, error)
+	MarkDepositRefunding(ctx context.Context, arg MarkDepositRefundingParams) (sql.Result, error)
+	MarkDepositSubmitted(ctx context.Context, arg MarkDepositSubmittedParams) (sql.Result, error)
+	RecordDepositError(ctx context.Context, arg RecordDepositErrorParams) error
+	RedriveDeposit(ctx context.Context, senderTxHash string) (sql.Result, error)
+	RejectDeposit(ctx context.Context, arg RejectDepositParams) (sql.Result, error)
+	RequestDepositApproval(ctx context.Context, arg RequestDepositApprovalParams) error
+	ResetDeposit(ctx context.Context, senderTxHash string) error
+	ScheduleDepositRetry(ctx context.Context, arg ScheduleDepositRetryParams) error
+	UpdateDepositStatus(ctx context.Context, arg UpdateDepositStatusParams) error
+	UpdateFeeSweepStatus(ctx context.Context, arg UpdateFeeSweepStatusParams) error
+	UpdateRefundStatus(ctx context.Context, arg UpdateRefundStatusParams) error
 }
 
 var _ Querier = (*Queries)(nil)
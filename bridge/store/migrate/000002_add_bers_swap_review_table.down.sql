@@ -0,0 +1 @@
+DROP TABLE IF EXISTS bers_swap_review;
\ No newline at end of file
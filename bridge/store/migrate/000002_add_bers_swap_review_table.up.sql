@@ -0,0 +1,5 @@
+CREATE TABLE `bers_swap_review` (
+  `sender_tx_hash` varchar(255) PRIMARY KEY,
+  `reason` varchar(255) NOT NULL,
+  `created_at` timestamp DEFAULT (now())
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS bers_deposit_queue;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE `bers_deposit_queue` (
+  `sender_tx_hash` varchar(255) PRIMARY KEY,
+  `block_number` bigint NOT NULL,
+  `block_hash` varchar(255) NOT NULL,
+  `deposit_nonce` bigint NOT NULL,
+  `sender_address` varchar(255) NOT NULL,
+  `receiver_address` varchar(255) NOT NULL,
+  `amount` decimal(65,0) NOT NULL,
+  `status` varchar(32) NOT NULL DEFAULT 'detected',
+  `created_at` timestamp DEFAULT (now()),
+  `updated_at` timestamp DEFAULT (now()) ON UPDATE CURRENT_TIMESTAMP,
+  INDEX `idx_bers_deposit_queue_status` (`status`, `block_number`)
+);
@@ -0,0 +1,6 @@
+DROP INDEX `idx_bers_deposit_queue_receiver_tx_hash` ON `bers_deposit_queue`;
+
+ALTER TABLE `bers_deposit_queue`
+  DROP COLUMN `receiver_tx_hash`,
+  DROP COLUMN `receiver_nonce`,
+  DROP COLUMN `reason`;
@@ -0,0 +1,6 @@
+ALTER TABLE `bers_deposit_queue`
+  ADD COLUMN `receiver_tx_hash` varchar(255),
+  ADD COLUMN `receiver_nonce` bigint,
+  ADD COLUMN `reason` varchar(255);
+
+CREATE INDEX `idx_bers_deposit_queue_receiver_tx_hash` ON `bers_deposit_queue` (`receiver_tx_hash`);
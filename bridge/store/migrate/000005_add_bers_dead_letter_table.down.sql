@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS bers_dead_letter;
+
+ALTER TABLE `bers_deposit_queue`
+  DROP COLUMN `retry_count`,
+  DROP COLUMN `next_retry_at`,
+  DROP COLUMN `last_error`;
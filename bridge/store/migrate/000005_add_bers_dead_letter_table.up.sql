@@ -0,0 +1,15 @@
+ALTER TABLE `bers_deposit_queue`
+  ADD COLUMN `retry_count` int NOT NULL DEFAULT 0,
+  ADD COLUMN `next_retry_at` timestamp NULL,
+  ADD COLUMN `last_error` text;
+
+CREATE TABLE `bers_dead_letter` (
+  `sender_tx_hash` varchar(255) PRIMARY KEY,
+  `block_number` bigint NOT NULL,
+  `sender_address` varchar(255) NOT NULL,
+  `receiver_address` varchar(255) NOT NULL,
+  `amount` decimal(65,0) NOT NULL,
+  `retry_count` int NOT NULL,
+  `last_error` text,
+  `created_at` timestamp DEFAULT (now())
+);
@@ -0,0 +1,3 @@
+ALTER TABLE `bers_swap_hist`
+  DROP COLUMN `deposit_amount`,
+  DROP COLUMN `payout_amount`;
@@ -0,0 +1,3 @@
+ALTER TABLE `bers_swap_hist`
+  ADD COLUMN `deposit_amount` decimal(65,0) NOT NULL DEFAULT 0,
+  ADD COLUMN `payout_amount` decimal(65,0) NOT NULL DEFAULT 0;
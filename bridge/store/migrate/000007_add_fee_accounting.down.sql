@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS bers_fee_sweep;
+
+ALTER TABLE `bers_swap_hist`
+  DROP COLUMN `gross_amount`,
+  DROP COLUMN `fee_amount`;
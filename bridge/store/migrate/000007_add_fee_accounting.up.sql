@@ -0,0 +1,12 @@
+ALTER TABLE `bers_swap_hist`
+  ADD COLUMN `gross_amount` decimal(65,0) NOT NULL DEFAULT 0,
+  ADD COLUMN `fee_amount` decimal(65,0) NOT NULL DEFAULT 0;
+
+CREATE TABLE `bers_fee_sweep` (
+  `tx_hash` varchar(255) PRIMARY KEY,
+  `treasury_address` varchar(255) NOT NULL,
+  `amount` decimal(65,0) NOT NULL,
+  `status` varchar(32) NOT NULL DEFAULT 'submitted',
+  `created_at` timestamp DEFAULT (now()),
+  `updated_at` timestamp DEFAULT (now()) ON UPDATE CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+DROP INDEX `idx_bers_deposit_queue_sender` ON `bers_deposit_queue`;
@@ -0,0 +1 @@
+CREATE INDEX `idx_bers_deposit_queue_sender` ON `bers_deposit_queue` (`sender_address`, `status`, `updated_at`);
@@ -0,0 +1,2 @@
+ALTER TABLE `bers_deposit_queue`
+  DROP COLUMN `approved_at`;
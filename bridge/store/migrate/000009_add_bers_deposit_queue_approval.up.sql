@@ -0,0 +1,2 @@
+ALTER TABLE `bers_deposit_queue`
+  ADD COLUMN `approved_at` timestamp NULL;
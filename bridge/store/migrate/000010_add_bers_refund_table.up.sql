@@ -0,0 +1,11 @@
+CREATE TABLE `bers_refund` (
+  `sender_tx_hash` varchar(255) PRIMARY KEY,
+  `tx_hash` varchar(255) NOT NULL,
+  `nonce` bigint NOT NULL,
+  `sender_address` varchar(255) NOT NULL,
+  `amount` decimal(65,0) NOT NULL,
+  `deposit_status` varchar(32) NOT NULL,
+  `status` varchar(32) NOT NULL DEFAULT 'submitted',
+  `created_at` timestamp DEFAULT (now()),
+  `updated_at` timestamp DEFAULT (now()) ON UPDATE CURRENT_TIMESTAMP
+);
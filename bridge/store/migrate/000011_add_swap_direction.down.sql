@@ -0,0 +1,7 @@
+DROP INDEX `idx_bers_deposit_queue_direction` ON `bers_deposit_queue`;
+
+ALTER TABLE `bers_swap_hist`
+  DROP COLUMN `direction`;
+
+ALTER TABLE `bers_deposit_queue`
+  DROP COLUMN `direction`;
@@ -0,0 +1,7 @@
+ALTER TABLE `bers_deposit_queue`
+  ADD COLUMN `direction` varchar(16) NOT NULL DEFAULT 'forward';
+
+ALTER TABLE `bers_swap_hist`
+  ADD COLUMN `direction` varchar(16) NOT NULL DEFAULT 'forward';
+
+CREATE INDEX `idx_bers_deposit_queue_direction` ON `bers_deposit_queue` (`direction`, `status`);
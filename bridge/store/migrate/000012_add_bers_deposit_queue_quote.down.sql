@@ -0,0 +1,4 @@
+ALTER TABLE `bers_deposit_queue`
+  DROP COLUMN `gross_amount`,
+  DROP COLUMN `fee_amount`,
+  DROP COLUMN `payout_amount`;
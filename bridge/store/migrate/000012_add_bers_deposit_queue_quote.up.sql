@@ -0,0 +1,4 @@
+ALTER TABLE `bers_deposit_queue`
+  ADD COLUMN `gross_amount` decimal(65,0) NULL,
+  ADD COLUMN `fee_amount` decimal(65,0) NULL,
+  ADD COLUMN `payout_amount` decimal(65,0) NULL;
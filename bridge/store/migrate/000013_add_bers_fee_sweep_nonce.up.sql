@@ -0,0 +1,2 @@
+ALTER TABLE `bers_fee_sweep`
+  ADD COLUMN `nonce` bigint NULL;
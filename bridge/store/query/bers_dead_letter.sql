@@ -0,0 +1,31 @@
+-- name: CreateDeadLetter :execresult
+INSERT INTO bers_dead_letter(
+    sender_tx_hash,
+    block_number,
+    sender_address,
+    receiver_address,
+    amount,
+    retry_count,
+    last_error
+) SELECT
+    sender_tx_hash,
+    block_number,
+    sender_address,
+    receiver_address,
+    amount,
+    retry_count,
+    last_error
+FROM bers_deposit_queue
+WHERE sender_tx_hash = ?;
+
+-- name: GetDeadLetter :one
+SELECT * FROM bers_dead_letter
+WHERE sender_tx_hash = ?;
+
+-- name: ListDeadLetters :many
+SELECT * FROM bers_dead_letter
+ORDER BY created_at;
+
+-- name: DeleteDeadLetter :execresult
+DELETE FROM bers_dead_letter
+WHERE sender_tx_hash = ?;
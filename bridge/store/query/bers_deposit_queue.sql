@@ -0,0 +1,100 @@
+-- name: EnqueueDeposit :execresult
+INSERT IGNORE INTO bers_deposit_queue(
+    sender_tx_hash,
+    block_number,
+    block_hash,
+    deposit_nonce,
+    sender_address,
+    receiver_address,
+    amount,
+    direction
+) VALUES (
+    ?,?,?,?,?,?,?,?
+);
+
+-- name: GetDeposit :one
+SELECT * FROM bers_deposit_queue
+WHERE sender_tx_hash = ?;
+
+-- name: ListDepositsByStatus :many
+SELECT * FROM bers_deposit_queue
+WHERE direction = ? AND status = ?
+ORDER BY block_number, deposit_nonce
+LIMIT ?;
+
+-- name: UpdateDepositStatus :exec
+UPDATE bers_deposit_queue SET status = ?
+WHERE sender_tx_hash = ?;
+
+-- name: MarkDepositSubmitted :execresult
+UPDATE bers_deposit_queue SET status = 'submitted', receiver_tx_hash = ?, receiver_nonce = ?, gross_amount = ?, fee_amount = ?, payout_amount = ?
+WHERE sender_tx_hash = ? AND status = 'detected';
+
+-- name: ResetDeposit :exec
+UPDATE bers_deposit_queue SET status = 'detected', receiver_tx_hash = NULL, receiver_nonce = NULL, gross_amount = NULL, fee_amount = NULL, payout_amount = NULL
+WHERE sender_tx_hash = ?;
+
+-- name: FailDeposit :exec
+UPDATE bers_deposit_queue SET status = 'failed', reason = ?
+WHERE sender_tx_hash = ?;
+
+-- name: HoldDeposit :exec
+UPDATE bers_deposit_queue SET status = 'held', reason = ?
+WHERE sender_tx_hash = ? AND status IN ('detected', 'pending_approval');
+
+-- name: HoldSubmittedDeposit :exec
+UPDATE bers_deposit_queue SET status = 'held', reason = ?
+WHERE sender_tx_hash = ? AND status = 'submitted';
+
+-- name: ListPendingDeposits :many
+SELECT * FROM bers_deposit_queue
+WHERE direction = ? AND status = 'detected' AND (next_retry_at IS NULL OR next_retry_at <= NOW())
+ORDER BY block_number, deposit_nonce
+LIMIT ?;
+
+-- name: ScheduleDepositRetry :exec
+UPDATE bers_deposit_queue
+SET status = 'detected', receiver_tx_hash = NULL, receiver_nonce = NULL,
+    retry_count = sqlc.arg(retry_count), last_error = sqlc.arg(last_error),
+    next_retry_at = NOW() + INTERVAL sqlc.arg(backoff_seconds) SECOND
+WHERE sender_tx_hash = sqlc.arg(sender_tx_hash);
+
+-- name: RecordDepositError :exec
+UPDATE bers_deposit_queue SET retry_count = ?, last_error = ?
+WHERE sender_tx_hash = ?;
+
+-- name: RedriveDeposit :execresult
+UPDATE bers_deposit_queue
+SET status = 'detected', receiver_tx_hash = NULL, receiver_nonce = NULL,
+    retry_count = 0, last_error = NULL, next_retry_at = NULL, reason = NULL
+WHERE sender_tx_hash = ? AND status = 'failed';
+
+-- name: GetDepositVolume :one
+SELECT CAST(COALESCE(SUM(amount), 0) AS CHAR) AS volume FROM bers_deposit_queue
+WHERE direction = ? AND status IN ('submitted', 'confirmed') AND updated_at >= NOW() - INTERVAL 1 DAY;
+
+-- name: GetDepositVolumeBySender :one
+SELECT CAST(COALESCE(SUM(amount), 0) AS CHAR) AS volume FROM bers_deposit_queue
+WHERE direction = ? AND sender_address = ? AND status IN ('submitted', 'confirmed') AND updated_at >= NOW() - INTERVAL 1 DAY;
+
+-- name: RequestDepositApproval :exec
+UPDATE bers_deposit_queue SET status = 'pending_approval', reason = ?
+WHERE sender_tx_hash = ? AND status = 'detected';
+
+-- name: ApproveDeposit :execresult
+UPDATE bers_deposit_queue SET status = 'detected', approved_at = NOW(), next_retry_at = NULL
+WHERE sender_tx_hash = ? AND status IN ('pending_approval', 'held');
+
+-- name: RejectDeposit :execresult
+UPDATE bers_deposit_queue SET status = 'rejected', reason = ?
+WHERE sender_tx_hash = ? AND status IN ('pending_approval', 'held');
+
+-- name: ListRefundableDeposits :many
+SELECT * FROM bers_deposit_queue
+WHERE direction = 'forward' AND status IN ('failed', 'rejected') AND updated_at <= NOW() - INTERVAL sqlc.arg(delay_seconds) SECOND
+ORDER BY block_number, deposit_nonce
+LIMIT sqlc.arg(limit);
+
+-- name: MarkDepositRefunding :execresult
+UPDATE bers_deposit_queue SET status = 'refunding'
+WHERE sender_tx_hash = ? AND status = ?;
@@ -0,0 +1,33 @@
+-- name: CreateFeeSweep :execresult
+INSERT INTO bers_fee_sweep(
+    tx_hash,
+    treasury_address,
+    amount,
+    nonce
+) VALUES (
+    ?,?,?,?
+);
+
+-- name: UpdateFeeSweepStatus :exec
+UPDATE bers_fee_sweep SET status = ?
+WHERE tx_hash = ?;
+
+-- name: ListFeeSweeps :many
+SELECT * FROM bers_fee_sweep
+ORDER BY created_at;
+
+-- name: ListFeeSweepsByStatus :many
+SELECT * FROM bers_fee_sweep
+WHERE status = ?
+ORDER BY created_at;
+
+-- name: LockSubmittedFeeSweeps :many
+SELECT tx_hash FROM bers_fee_sweep
+WHERE status = 'submitted'
+FOR UPDATE;
+
+-- name: GetFeeBalance :one
+SELECT CAST(
+    (SELECT COALESCE(SUM(fee_amount), 0) FROM bers_swap_hist) -
+    (SELECT COALESCE(SUM(amount), 0) FROM bers_fee_sweep WHERE status <> 'failed')
+AS CHAR) AS balance;
@@ -0,0 +1,29 @@
+-- name: CreateRefund :exec
+INSERT INTO bers_refund(
+    sender_tx_hash,
+    tx_hash,
+    nonce,
+    sender_address,
+    amount,
+    deposit_status
+) VALUES (
+    ?,?,?,?,?,?
+) ON DUPLICATE KEY UPDATE
+    tx_hash = VALUES(tx_hash),
+    nonce = VALUES(nonce),
+    amount = VALUES(amount),
+    deposit_status = VALUES(deposit_status),
+    status = 'submitted';
+
+-- name: GetRefund :one
+SELECT * FROM bers_refund
+WHERE sender_tx_hash = ?;
+
+-- name: ListRefundsByStatus :many
+SELECT * FROM bers_refund
+WHERE status = ?
+ORDER BY nonce;
+
+-- name: UpdateRefundStatus :exec
+UPDATE bers_refund SET status = ?
+WHERE sender_tx_hash = ?;
@@ -3,9 +3,14 @@ INSERT INTO bers_swap_hist(
     sender_tx_hash,
     receiver_tx_hash,
     amount,
-    berith_address
+    berith_address,
+    deposit_amount,
+    gross_amount,
+    fee_amount,
+    payout_amount,
+    direction
 ) VALUES (
-    ?,?,?,?
+    ?,?,?,?,?,?,?,?,?
 );
 
 -- name: GetBersSwapHistory :one
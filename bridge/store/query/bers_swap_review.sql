@@ -0,0 +1,15 @@
+-- name: CreateSwapReview :execresult
+INSERT INTO bers_swap_review(
+    sender_tx_hash,
+    reason
+) VALUES (
+    ?,?
+) ON DUPLICATE KEY UPDATE reason = VALUES(reason);
+
+-- name: GetSwapReview :one
+SELECT * FROM bers_swap_review
+WHERE sender_tx_hash = ?;
+
+-- name: ListSwapReviews :many
+SELECT * FROM bers_swap_review
+ORDER BY created_at;
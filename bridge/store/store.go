@@ -11,6 +11,43 @@ const (
 	DBDriver = "mysql"
 )
 
+// deposit queue의 상태
+// detected -> submitted -> confirmed 순서로 진행되며, 전송이 실패하면 failed, 운영자 확인이 필요하면 held 상태가 됩니다.
+// 운영자 승인이 필요한 deposit은 pending_approval 상태가 되며, 승인되면 detected, 거절되면 rejected 상태가 됩니다.
+// failed, rejected 상태의 deposit은 BERS를 환불하면 refunding -> refunded 상태가 됩니다.
+const (
+	DepositDetected        = "detected"         // 감지되어 지급을 기다리는 상태
+	DepositSubmitted       = "submitted"        // 토큰 전송 트랜잭션이 서명되어 전송된 상태
+	DepositConfirmed       = "confirmed"        // 토큰 전송이 완료되어 swap history가 저장된 상태
+	DepositFailed          = "failed"           // 토큰 전송 트랜잭션이 실패한 상태
+	DepositHeld            = "held"             // reorg 등으로 운영자 확인이 필요하여 보류된 상태
+	DepositPendingApproval = "pending_approval" // 지급 전 운영자 승인을 기다리는 상태
+	DepositRejected        = "rejected"         // 운영자가 지급을 거절한 상태
+	DepositRefunding       = "refunding"        // 환불 트랜잭션이 서명되어 전송된 상태
+	DepositRefunded        = "refunded"         // sender에게 BERS 환불이 완료된 상태
+	DepositInvalid         = "invalid"
+)
+
+// 수수료 sweep 트랜잭션의 상태
+const (
+	FeeSweepSubmitted = "submitted"
+	FeeSweepConfirmed = "confirmed"
+	FeeSweepFailed    = "failed"
+)
+
+// deposit queue와 swap history의 swap 방향
+const (
+	DirectionForward = "forward" // Berith chain의 BERS를 예치하고 Klaytn chain의 토큰을 지급
+	DirectionReverse = "reverse" // Klaytn chain의 토큰을 반환하고 Berith chain의 BERS를 지급
+)
+
+// 환불 트랜잭션의 상태
+const (
+	RefundSubmitted = "submitted"
+	RefundConfirmed = "confirmed"
+	RefundFailed    = "failed"
+)
+
 type Store struct {
 	mariadb.Queries
 	db *sql.DB
@@ -21,11 +58,15 @@ func NewStore(source string) (*Store, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewStoreFromDB(conn), nil
+}
 
+// NewStoreFromDB는 이미 연결된 db로 Store를 생성합니다.
+func NewStoreFromDB(db *sql.DB) *Store {
 	return &Store{
-		Queries: *mariadb.New(conn),
-		db:      conn,
-	}, nil
+		Queries: *mariadb.New(db),
+		db:      db,
+	}
 }
 
 func (store *Store) execTx(ctx context.Context, fn func(*mariadb.Queries) error) error {
@@ -3,6 +3,17 @@ package store
 import (
 	"berith-swap/bridge/store/mariadb"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// ErrDepositNotDetected는 submitted 상태로 변경하려는 deposit이 detected 상태가 아닐 때 반환됩니다.
+	ErrDepositNotDetected = errors.New("deposit is not detected")
+	// ErrFeeSweepPending은 결과를 확인하지 못한 수수료 sweep이 남아있을 때 반환됩니다.
+	ErrFeeSweepPending = errors.New("previous fee sweep is not resolved")
 )
 
 func (s *Store) CreateSwapHistoryTx(ctx context.Context, arg mariadb.CreateBersSwapHistoryParams) error {
@@ -15,3 +26,261 @@ func (s *Store) CreateSwapHistoryTx(ctx context.Context, arg mariadb.CreateBersS
 	})
 	return err
 }
+
+// CompleteDepositTx는 swap history를 저장하고 deposit queue의 상태를 confirmed로 변경합니다.
+func (s *Store) CompleteDepositTx(ctx context.Context, arg mariadb.CreateBersSwapHistoryParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		_, err := q.CreateBersSwapHistory(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return q.UpdateDepositStatus(ctx, mariadb.UpdateDepositStatusParams{
+			Status:       DepositConfirmed,
+			SenderTxHash: arg.SenderTxHash,
+		})
+	})
+	return err
+}
+
+// MarkDepositsSubmittedTx는 한 트랜잭션으로 전송할 deposit들을 submitted 상태로 변경하고 트랜잭션의 hash와 nonce를 저장합니다.
+// detected 상태가 아닌 deposit이 하나라도 있다면 이미 다른 곳에서 처리 중이거나 보류된 deposit이므로 모두 되돌리고 ErrDepositNotDetected를 반환합니다.
+func (s *Store) MarkDepositsSubmittedTx(ctx context.Context, args ...mariadb.MarkDepositSubmittedParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		for _, arg := range args {
+			res, err := q.MarkDepositSubmitted(ctx, arg)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil || n == 0 {
+				return fmt.Errorf("%w. hash:%s", ErrDepositNotDetected, arg.SenderTxHash)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// HoldDepositTx는 deposit을 수동 검토 대상으로 기록하고, 아직 지급되지 않은 deposit이라면 queue에서 held 상태로 보류합니다.
+func (s *Store) HoldDepositTx(ctx context.Context, arg mariadb.CreateSwapReviewParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		_, err := q.CreateSwapReview(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return q.HoldDeposit(ctx, mariadb.HoldDepositParams{
+			Reason:       sql.NullString{String: arg.Reason, Valid: true},
+			SenderTxHash: arg.SenderTxHash,
+		})
+	})
+	return err
+}
+
+// HoldSubmittedDepositTx는 지급 여부를 확인할 수 없는 submitted 상태의 deposit을 수동 검토 대상으로 기록하고 held 상태로 보류합니다.
+// 운영자가 확인할 수 있도록 토큰 전송 트랜잭션의 hash와 nonce는 그대로 남겨둡니다.
+func (s *Store) HoldSubmittedDepositTx(ctx context.Context, arg mariadb.CreateSwapReviewParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		_, err := q.CreateSwapReview(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return q.HoldSubmittedDeposit(ctx, mariadb.HoldSubmittedDepositParams{
+			Reason:       sql.NullString{String: arg.Reason, Valid: true},
+			SenderTxHash: arg.SenderTxHash,
+		})
+	})
+	return err
+}
+
+// EnqueueHeldDepositTx는 deposit을 queue에 저장하는 동시에 수동 검토 대상으로 기록하고 held 상태로 보류합니다.
+// 이미 저장된 deposit이라면 상태를 변경하지 않습니다.
+func (s *Store) EnqueueHeldDepositTx(ctx context.Context, arg mariadb.EnqueueDepositParams, reason string) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		res, err := q.EnqueueDeposit(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return err
+		}
+		_, err = q.CreateSwapReview(ctx, mariadb.CreateSwapReviewParams{
+			SenderTxHash: arg.SenderTxHash,
+			Reason:       reason,
+		})
+		if err != nil {
+			return err
+		}
+		return q.HoldDeposit(ctx, mariadb.HoldDepositParams{
+			Reason:       sql.NullString{String: reason, Valid: true},
+			SenderTxHash: arg.SenderTxHash,
+		})
+	})
+	return err
+}
+
+// DeadLetterDepositTx는 재시도 횟수를 초과한 deposit을 failed 상태로 변경하고 dead letter 테이블로 옮깁니다.
+func (s *Store) DeadLetterDepositTx(ctx context.Context, arg mariadb.RecordDepositErrorParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		err := q.RecordDepositError(ctx, arg)
+		if err != nil {
+			return err
+		}
+		err = q.FailDeposit(ctx, mariadb.FailDepositParams{
+			Reason:       sql.NullString{String: "retry limit exceeded", Valid: true},
+			SenderTxHash: arg.SenderTxHash,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateDeadLetter(ctx, arg.SenderTxHash)
+		return err
+	})
+	return err
+}
+
+// RedriveDeadLetterTx는 dead letter 테이블의 deposit을 삭제하고 다시 지급되도록 detected 상태로 되돌립니다.
+func (s *Store) RedriveDeadLetterTx(ctx context.Context, senderTxHash string) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		res, err := q.DeleteDeadLetter(ctx, senderTxHash)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("dead letter not found. hash:%s", senderTxHash)
+		}
+
+		res, err = q.RedriveDeposit(ctx, senderTxHash)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("failed deposit not found in deposit queue. hash:%s", senderTxHash)
+		}
+		return nil
+	})
+	return err
+}
+
+// ApproveDepositTx는 승인을 기다리거나 보류된 deposit을 승인하여 다음 queue 조회에서 지급되도록 detected 상태로 되돌립니다.
+func (s *Store) ApproveDepositTx(ctx context.Context, senderTxHash string) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		res, err := q.ApproveDeposit(ctx, senderTxHash)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("deposit pending approval not found. hash:%s", senderTxHash)
+		}
+		return nil
+	})
+	return err
+}
+
+// RejectDepositTx는 승인을 기다리거나 보류된 deposit을 거절하고 거절 사유를 수동 검토 기록에 남깁니다.
+func (s *Store) RejectDepositTx(ctx context.Context, arg mariadb.CreateSwapReviewParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		res, err := q.RejectDeposit(ctx, mariadb.RejectDepositParams{
+			Reason:       sql.NullString{String: arg.Reason, Valid: true},
+			SenderTxHash: arg.SenderTxHash,
+		})
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("deposit pending approval not found. hash:%s", arg.SenderTxHash)
+		}
+		_, err = q.CreateSwapReview(ctx, arg)
+		return err
+	})
+	return err
+}
+
+// RefundDepositTx는 환불 트랜잭션을 기록하고 deposit을 refunding 상태로 변경합니다.
+// deposit의 상태가 arg.DepositStatus가 아니라면 이미 처리 중이거나 환불할 수 없는 deposit이므로 기록하지 않습니다.
+func (s *Store) RefundDepositTx(ctx context.Context, arg mariadb.CreateRefundParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		res, err := q.MarkDepositRefunding(ctx, mariadb.MarkDepositRefundingParams{
+			SenderTxHash: arg.SenderTxHash,
+			Status:       arg.DepositStatus,
+		})
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("refundable deposit not found. hash:%s, status:%s", arg.SenderTxHash, arg.DepositStatus)
+		}
+		return q.CreateRefund(ctx, arg)
+	})
+	return err
+}
+
+// CompleteRefundTx는 환불 트랜잭션을 confirmed로, deposit을 refunded 상태로 변경하고 dead letter 테이블에서 삭제합니다.
+func (s *Store) CompleteRefundTx(ctx context.Context, senderTxHash string) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		err := q.UpdateRefundStatus(ctx, mariadb.UpdateRefundStatusParams{
+			Status:       RefundConfirmed,
+			SenderTxHash: senderTxHash,
+		})
+		if err != nil {
+			return err
+		}
+		err = q.UpdateDepositStatus(ctx, mariadb.UpdateDepositStatusParams{
+			Status:       DepositRefunded,
+			SenderTxHash: senderTxHash,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = q.DeleteDeadLetter(ctx, senderTxHash)
+		return err
+	})
+	return err
+}
+
+// FailRefundTx는 환불 트랜잭션을 failed로 변경하고 deposit을 환불 전 상태로 되돌려 다시 환불할 수 있도록 합니다.
+func (s *Store) FailRefundTx(ctx context.Context, refund mariadb.BersRefund) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		err := q.UpdateRefundStatus(ctx, mariadb.UpdateRefundStatusParams{
+			Status:       RefundFailed,
+			SenderTxHash: refund.SenderTxHash,
+		})
+		if err != nil {
+			return err
+		}
+		return q.UpdateDepositStatus(ctx, mariadb.UpdateDepositStatusParams{
+			Status:       refund.DepositStatus,
+			SenderTxHash: refund.SenderTxHash,
+		})
+	})
+	return err
+}
+
+// CreateFeeSweepTx는 submitted 상태의 수수료 sweep을 잠근 채로 sweep 할 수 있는 수수료를 다시 확인하고 sweep을 기록합니다.
+// 결과를 확인하지 못한 sweep이 남아있다면 ErrFeeSweepPending을, sweep 할 양이 남은 수수료보다 많다면 에러를 반환합니다.
+func (s *Store) CreateFeeSweepTx(ctx context.Context, arg mariadb.CreateFeeSweepParams) error {
+	err := s.execTx(ctx, func(q *mariadb.Queries) error {
+		pending, err := q.LockSubmittedFeeSweeps(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%w. tx:%s", ErrFeeSweepPending, pending[0])
+		}
+
+		balance, err := q.GetFeeBalance(ctx)
+		if err != nil {
+			return err
+		}
+		available, ok := new(big.Int).SetString(balance, 10)
+		if !ok {
+			return fmt.Errorf("invalid fee balance:%s", balance)
+		}
+		amount, ok := new(big.Int).SetString(arg.Amount, 10)
+		if !ok || amount.Cmp(available) > 0 {
+			return fmt.Errorf("fee sweep exceeds fee balance. amount:%s, balance:%s", arg.Amount, balance)
+		}
+
+		_, err = q.CreateFeeSweep(ctx, arg)
+		return err
+	})
+	return err
+}
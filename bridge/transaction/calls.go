@@ -26,6 +26,8 @@ type GasPricer interface {
 type ClientDispatcher interface {
 	WaitAndReturnTxReceipt(h common.Hash) (*types.Receipt, error)
 	SignAndSendTransaction(ctx context.Context, tx CommonTransaction) (common.Hash, error)
+	SignTransaction(ctx context.Context, tx CommonTransaction) ([]byte, error)
+	SendSignedTransaction(ctx context.Context, tx CommonTransaction, rawTx []byte) (common.Hash, error)
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
 	GetTransactionByHash(h common.Hash) (tx *types.Transaction, isPending bool, err error)
 	UnsafeNonce() (*big.Int, error)
@@ -24,6 +24,9 @@ type TransactOptions struct {
 	Nonce    *big.Int
 	ChainID  *big.Int
 	Priority uint8
+	// OnSigned는 서명된 트랜잭션을 전송하기 직전에 호출됩니다.
+	// 에러를 반환하면 트랜잭션은 전송되지 않습니다.
+	OnSigned func(hash common.Hash, nonce uint64) error
 }
 
 var TxPriorities = map[string]uint8{
@@ -43,6 +46,7 @@ func MergeTransactionOptions(primary *TransactOptions, additional *TransactOptio
 
 type Transactor interface {
 	Transact(to *common.Address, data []byte, opts TransactOptions) (*common.Hash, error)
+	Submit(to *common.Address, data []byte, opts TransactOptions) (*common.Hash, error)
 }
 
 type signAndSendTransactor struct {
@@ -59,7 +63,24 @@ func NewSignAndSendTransactor(txFabric TxFabric, gasPriceClient GasPricer, clien
 	}
 }
 
+// Transact는 트랜잭션을 전송하고 receipt를 받을 때까지 기다립니다.
 func (t *signAndSendTransactor) Transact(to *common.Address, data []byte, opts TransactOptions) (*common.Hash, error) {
+	h, err := t.Submit(to, data, opts)
+	if err != nil {
+		return &common.Hash{}, err
+	}
+
+	_, err = t.client.WaitAndReturnTxReceipt(*h)
+	if err != nil {
+		return &common.Hash{}, err
+	}
+
+	return h, nil
+}
+
+// Submit은 nonce를 할당하여 트랜잭션을 서명 및 전송하고 receipt를 기다리지 않고 반환합니다.
+// 연속으로 호출하면 순차적인 nonce로 여러 트랜잭션을 동시에 전송할 수 있습니다.
+func (t *signAndSendTransactor) Submit(to *common.Address, data []byte, opts TransactOptions) (*common.Hash, error) {
 	t.client.LockNonce()
 	n, err := t.client.UnsafeNonce()
 	if err != nil {
@@ -88,20 +109,29 @@ func (t *signAndSendTransactor) Transact(to *common.Address, data []byte, opts T
 		return &common.Hash{}, err
 	}
 
-	h, err := t.client.SignAndSendTransaction(context.TODO(), tx)
+	rawTx, err := t.client.SignTransaction(context.TODO(), tx)
 	if err != nil {
 		t.client.UnlockNonce()
-		log.Error().Err(err)
 		return &common.Hash{}, err
 	}
 
-	err = t.client.UnsafeIncreaseNonce()
-	t.client.UnlockNonce()
+	if opts.OnSigned != nil {
+		err = opts.OnSigned(tx.Hash(), n.Uint64())
+		if err != nil {
+			t.client.UnlockNonce()
+			return &common.Hash{}, err
+		}
+	}
+
+	h, err := t.client.SendSignedTransaction(context.TODO(), tx, rawTx)
 	if err != nil {
+		t.client.UnlockNonce()
+		log.Error().Err(err)
 		return &common.Hash{}, err
 	}
 
-	_, err = t.client.WaitAndReturnTxReceipt(h)
+	err = t.client.UnsafeIncreaseNonce()
+	t.client.UnlockNonce()
 	if err != nil {
 		return &common.Hash{}, err
 	}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+pragma solidity ^0.8.1;
+
+import "@openzeppelin/contracts/access/Ownable.sol";
+import "@openzeppelin/contracts/token/ERC20/IERC20.sol";
+
+contract BatchTransfer is Ownable {
+    function batchTransfer(
+        IERC20 token,
+        address[] calldata recipients,
+        uint256[] calldata amounts
+    ) external onlyOwner {
+        require(recipients.length == amounts.length, "BatchTransfer: length mismatch");
+
+        address from = owner();
+        for (uint256 i = 0; i < recipients.length; i++) {
+            require(token.transferFrom(from, recipients[i], amounts[i]), "BatchTransfer: transfer failed");
+        }
+    }
+}
@@ -4,7 +4,15 @@ import (
 	"berith-swap/bridge/bridge"
 	"berith-swap/bridge/cmd"
 	"berith-swap/bridge/config"
+	"berith-swap/bridge/store"
+	"berith-swap/bridge/store/mariadb"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/rs/zerolog/log"
@@ -25,10 +33,92 @@ var cliFlags = []cli.Flag{
 	cmd.BlockstorePathFlag,
 	cmd.LoadFlag,
 	cmd.DBSourceFlag,
+	cmd.StartBlockFlag,
+}
+
+var backfillCommand = &cli.Command{
+	Name:  "backfill",
+	Usage: "지정한 블록 구간의 Deposit 이벤트를 다시 탐색하여 누락된 swap을 처리합니다.",
+	Flags: []cli.Flag{
+		cmd.FromBlockFlag,
+		cmd.ToBlockFlag,
+		cmd.DryRunFlag,
+	},
+	Action: backfill,
+}
+
+var deadLetterCommand = &cli.Command{
+	Name:  "dead-letter",
+	Usage: "재시도 횟수를 초과하여 dead letter 테이블로 옮겨진 swap을 관리합니다.",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "list",
+			Usage:  "dead letter 테이블의 swap을 조회합니다.",
+			Action: listDeadLetters,
+		},
+		{
+			Name:      "redrive",
+			Usage:     "dead letter 테이블의 swap을 deposit queue로 되돌려 다시 지급합니다.",
+			ArgsUsage: "<sender tx hash>",
+			Action:    redriveDeadLetter,
+		},
+	},
+}
+
+var approveCommand = &cli.Command{
+	Name:      "approve",
+	Usage:     "운영자 승인을 기다리거나 보류된 swap을 승인하여 지급합니다.",
+	ArgsUsage: "<sender tx hash>",
+	Action:    approveDeposit,
+}
+
+var rejectCommand = &cli.Command{
+	Name:      "reject",
+	Usage:     "운영자 승인을 기다리거나 보류된 swap을 거절합니다.",
+	ArgsUsage: "<sender tx hash>",
+	Flags: []cli.Flag{
+		cmd.ReasonFlag,
+	},
+	Action: rejectDeposit,
+}
+
+var refundCommand = &cli.Command{
+	Name:      "refund",
+	Usage:     "지급에 실패했거나 거절된 swap의 BERS를 Berith chain의 sender에게 환불합니다.",
+	ArgsUsage: "<sender tx hash>",
+	Flags: []cli.Flag{
+		cmd.ForceFlag,
+	},
+	Action: refundDeposit,
+}
+
+var feeCommand = &cli.Command{
+	Name:  "fee",
+	Usage: "swap history에 누적된 bridge 수수료를 관리합니다.",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "balance",
+			Usage:  "아직 treasury로 sweep 되지 않은 수수료를 조회합니다.",
+			Action: feeBalance,
+		},
+		{
+			Name:   "sweep",
+			Usage:  "누적된 수수료를 treasury 주소로 전송합니다.",
+			Action: sweepFees,
+		},
+	},
 }
 
 func init() {
 	app.Action = run
+	app.Commands = []*cli.Command{
+		backfillCommand,
+		deadLetterCommand,
+		feeCommand,
+		approveCommand,
+		rejectCommand,
+		refundCommand,
+	}
 	app.Name = "berith-swap"
 	app.Usage = "BerithSwap"
 	app.Copyright = "Copyright 2023 Berith foundation Authors"
@@ -53,3 +143,163 @@ func run(ctx *cli.Context) error {
 	return b.Start()
 	//TODO: receiver tx 실패 시 sender 블록 스토어 롤백 적용하기
 }
+
+func backfill(ctx *cli.Context) error {
+	from := new(big.Int).SetUint64(ctx.Uint64(cmd.FromBlockFlag.Name))
+	to := new(big.Int).SetUint64(ctx.Uint64(cmd.ToBlockFlag.Name))
+	if from.Cmp(to) == 1 {
+		return fmt.Errorf("invalid block range. from:%s, to:%s", from.String(), to.String())
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	b := bridge.NewBridge(cfg)
+	defer b.Stop()
+	return b.Backfill(from, to, ctx.Bool(cmd.DryRunFlag.Name))
+}
+
+func listDeadLetters(ctx *cli.Context) error {
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := store.NewStore(cfg.DBSource)
+	if err != nil {
+		return err
+	}
+	defer st.Stop()
+
+	letters, err := st.ListDeadLetters(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SENDER TX\tRECEIVER\tAMOUNT\tRETRIES\tCREATED AT\tLAST ERROR")
+	for _, l := range letters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", l.SenderTxHash, l.ReceiverAddress, l.Amount, l.RetryCount, l.CreatedAt.Time.Format(time.RFC3339), l.LastError.String)
+	}
+	return w.Flush()
+}
+
+func redriveDeadLetter(ctx *cli.Context) error {
+	hash := ctx.Args().First()
+	if hash == "" {
+		return errors.New("sender tx hash was not provided")
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := store.NewStore(cfg.DBSource)
+	if err != nil {
+		return err
+	}
+	defer st.Stop()
+
+	err = st.RedriveDeadLetterTx(context.Background(), hash)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("redrive dead letter. sender tx:%s", hash)
+	return nil
+}
+
+func approveDeposit(ctx *cli.Context) error {
+	hash := ctx.Args().First()
+	if hash == "" {
+		return errors.New("sender tx hash was not provided")
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := store.NewStore(cfg.DBSource)
+	if err != nil {
+		return err
+	}
+	defer st.Stop()
+
+	err = st.ApproveDepositTx(context.Background(), hash)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("approved deposit. it will be paid on the next queue poll. sender tx:%s", hash)
+	return nil
+}
+
+func rejectDeposit(ctx *cli.Context) error {
+	hash := ctx.Args().First()
+	if hash == "" {
+		return errors.New("sender tx hash was not provided")
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := store.NewStore(cfg.DBSource)
+	if err != nil {
+		return err
+	}
+	defer st.Stop()
+
+	reason := ctx.String(cmd.ReasonFlag.Name)
+	err = st.RejectDepositTx(context.Background(), mariadb.CreateSwapReviewParams{
+		SenderTxHash: hash,
+		Reason:       reason,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("rejected deposit. sender tx:%s, reason:%s", hash, reason)
+	return nil
+}
+
+func refundDeposit(ctx *cli.Context) error {
+	hash := ctx.Args().First()
+	if hash == "" {
+		return errors.New("sender tx hash was not provided")
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	b := bridge.NewBridge(cfg)
+	defer b.Stop()
+	return b.Refund(hash, ctx.Bool(cmd.ForceFlag.Name))
+}
+
+func feeBalance(ctx *cli.Context) error {
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	st, err := store.NewStore(cfg.DBSource)
+	if err != nil {
+		return err
+	}
+	defer st.Stop()
+
+	balance, err := st.GetFeeBalance(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Println(balance)
+	return nil
+}
+
+func sweepFees(ctx *cli.Context) error {
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	b := bridge.NewBridge(cfg)
+	defer b.Stop()
+	return b.SweepFees()
+}